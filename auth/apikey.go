@@ -0,0 +1,95 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"fmt"
+	"go-wire/config"
+	"net/http"
+	"time"
+)
+
+const (
+	APIKeyName   = "apikey"
+	APIKeyHeader = "X-Api-Key"
+	// legacyHeader 兼容旧版 clientId 请求头
+	legacyHeader = "clientId"
+)
+
+type apiKey struct {
+	name      string
+	hash      [sha256.Size]byte
+	roles     []string
+	scopes    []string
+	expiresAt time.Time
+}
+
+// apiKeyAuthenticator 静态 API Key 认证
+type apiKeyAuthenticator struct {
+	keys []apiKey
+}
+
+func newAPIKeyAuthenticator(cfg *config.Config) (*apiKeyAuthenticator, error) {
+	a := &apiKeyAuthenticator{keys: make([]apiKey, 0, len(cfg.Auth.APIKeys)+1)}
+	for _, k := range cfg.Auth.APIKeys {
+		key := apiKey{
+			name:   k.Name,
+			hash:   sha256.Sum256([]byte(k.Key)),
+			roles:  k.Roles,
+			scopes: k.Scopes,
+		}
+		if k.ExpiresAt != "" {
+			t, err := time.Parse(time.RFC3339, k.ExpiresAt)
+			if err != nil {
+				return nil, fmt.Errorf("API Key [%s] 过期时间解析失败: %w", k.Name, err)
+			}
+			key.expiresAt = t
+		}
+		a.keys = append(a.keys, key)
+	}
+	// 兼容 App.ClientID 配置，scope 需在 App.ClientScopes 中显式列出
+	if cfg.App.ClientID != "" {
+		a.keys = append(a.keys, apiKey{
+			name:   "default",
+			hash:   sha256.Sum256([]byte(cfg.App.ClientID)),
+			scopes: cfg.App.ClientScopes,
+		})
+	}
+	return a, nil
+}
+
+func (a *apiKeyAuthenticator) Name() string {
+	return APIKeyName
+}
+
+func (a *apiKeyAuthenticator) Authenticate(r *http.Request) (*Principal, error) {
+	value := r.Header.Get(APIKeyHeader)
+	if value == "" {
+		value = r.Header.Get(legacyHeader)
+	}
+	if value == "" {
+		return nil, ErrNoCredentials
+	}
+
+	// 比较摘要以消除长度差异，并遍历全部 key 避免时序泄露
+	// 与兼容 ClientID 相同的 key 以 APIKeys 中的配置为准
+	hash := sha256.Sum256([]byte(value))
+	var matched *apiKey
+	for i := range a.keys {
+		if subtle.ConstantTimeCompare(hash[:], a.keys[i].hash[:]) == 1 && matched == nil {
+			matched = &a.keys[i]
+		}
+	}
+	if matched == nil {
+		return nil, ErrInvalidCredentials
+	}
+	if !matched.expiresAt.IsZero() && time.Now().After(matched.expiresAt) {
+		return nil, ErrExpiredCredentials
+	}
+	return &Principal{
+		ID:     matched.name,
+		Type:   APIKeyName,
+		Roles:  matched.roles,
+		Scopes: matched.scopes,
+	}, nil
+}
@@ -0,0 +1,46 @@
+package auth
+
+import (
+	"errors"
+	"go-wire/reqctx"
+	"net/http"
+	"time"
+
+	"github.com/google/wire"
+)
+
+var ProviderSet = wire.NewSet(NewManager, NewAuthorizer)
+
+var (
+	ErrNoCredentials      = errors.New("未携带认证信息")
+	ErrInvalidCredentials = errors.New("认证信息无效")
+	ErrExpiredCredentials = errors.New("认证信息已过期")
+)
+
+// Principal 请求主体，定义在 reqctx 中，service 与 repo 通过 reqctx.PrincipalFrom 读取而无需依赖 auth
+type Principal = reqctx.Principal
+
+// Authenticator 认证器
+// 请求未携带该认证器所需凭证时返回 ErrNoCredentials，交由认证链中下一个认证器处理
+type Authenticator interface {
+	Name() string
+	Authenticate(r *http.Request) (*Principal, error)
+}
+
+// Refresher 支持滑动续期的认证器，需要续期时返回新凭证及其过期时间
+type Refresher interface {
+	Refresh(p *Principal) (token string, expiresAt time.Time, ok bool, err error)
+}
+
+const AnonymousName = reqctx.Anonymous
+
+// anonymousAuthenticator 匿名认证，总是成功，一般放在认证链末尾
+type anonymousAuthenticator struct{}
+
+func (anonymousAuthenticator) Name() string {
+	return AnonymousName
+}
+
+func (anonymousAuthenticator) Authenticate(*http.Request) (*Principal, error) {
+	return &Principal{ID: AnonymousName, Type: AnonymousName}, nil
+}
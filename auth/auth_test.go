@@ -0,0 +1,89 @@
+package auth
+
+import (
+	"errors"
+	"go-wire/config"
+	"go-wire/logger"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func newRequest(path string, header ...string) *http.Request {
+	r := httptest.NewRequest(http.MethodGet, path, nil)
+	for i := 0; i+1 < len(header); i += 2 {
+		r.Header.Set(header[i], header[i+1])
+	}
+	return r
+}
+
+func TestAPIKeyAuthenticator(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.App.ClientID = "legacy"
+	cfg.Auth.APIKeys = []config.APIKeyConfig{
+		{Name: "frontend", Key: "k1", Scopes: []string{"api:read"}},
+		{Name: "old", Key: "k2", ExpiresAt: time.Now().Add(-time.Hour).Format(time.RFC3339)},
+	}
+	a, err := newAPIKeyAuthenticator(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := a.Authenticate(newRequest("/", APIKeyHeader, "k1"))
+	if err != nil || p.ID != "frontend" || !p.HasScope("api:read") {
+		t.Fatalf("有效 key: principal=%+v err=%v", p, err)
+	}
+	if _, err = a.Authenticate(newRequest("/", APIKeyHeader, "bad")); !errors.Is(err, ErrInvalidCredentials) {
+		t.Fatalf("无效 key: err=%v", err)
+	}
+	if _, err = a.Authenticate(newRequest("/", APIKeyHeader, "k2")); !errors.Is(err, ErrExpiredCredentials) {
+		t.Fatalf("过期 key: err=%v", err)
+	}
+	if _, err = a.Authenticate(newRequest("/")); !errors.Is(err, ErrNoCredentials) {
+		t.Fatalf("未携带 key: err=%v", err)
+	}
+
+	// 兼容的 clientId 默认不具备任何 scope
+	p, err = a.Authenticate(newRequest("/", "clientId", "legacy"))
+	if err != nil || p.ID != "default" || len(p.Scopes) != 0 {
+		t.Fatalf("clientId: principal=%+v err=%v", p, err)
+	}
+	cfg.App.ClientScopes = []string{"api:read"}
+	if a, err = newAPIKeyAuthenticator(cfg); err != nil {
+		t.Fatal(err)
+	}
+	if p, _ = a.Authenticate(newRequest("/", "clientId", "legacy")); !p.HasScope("api:read") || p.HasScope("api:write") {
+		t.Fatalf("clientId 配置 scope: principal=%+v", p)
+	}
+}
+
+func TestManagerChain(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Auth.Authenticators = []string{AnonymousName}
+	cfg.Auth.Groups = []config.AuthGroup{{Prefix: "/api", Authenticators: []string{APIKeyName}}}
+	cfg.Auth.APIKeys = []config.APIKeyConfig{{Name: "frontend", Key: "k1"}}
+	m, err := NewManager(cfg, logger.NewNop(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if p, err := m.Authenticate(newRequest("/health")); err != nil || !p.Anonymous() {
+		t.Fatalf("默认认证链: principal=%+v err=%v", p, err)
+	}
+	if _, err := m.Authenticate(newRequest("/api/test")); !errors.Is(err, ErrNoCredentials) {
+		t.Fatalf("/api 未携带凭证: err=%v", err)
+	}
+	if p, err := m.Authenticate(newRequest("/api/test", APIKeyHeader, "k1")); err != nil || p.ID != "frontend" {
+		t.Fatalf("/api 携带凭证: principal=%+v err=%v", p, err)
+	}
+	// 前缀按路径段匹配
+	if p, err := m.Authenticate(newRequest("/apix")); err != nil || !p.Anonymous() {
+		t.Fatalf("/apix: principal=%+v err=%v", p, err)
+	}
+
+	cfg.Auth.Groups = []config.AuthGroup{{Prefix: "/api", Authenticators: []string{"missing"}}}
+	if _, err := NewManager(cfg, logger.NewNop(), nil); err == nil {
+		t.Fatal("引用未注册的认证器应返回错误")
+	}
+}
@@ -0,0 +1,188 @@
+package auth
+
+import (
+	"go-wire/errs"
+	"go-wire/logger"
+	"go-wire/reqctx"
+	"go-wire/response"
+	"net/http"
+	"path"
+	"sort"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RoutePolicy 路由与其授权策略，用于审计
+type RoutePolicy struct {
+	Method   string   `json:"method"`
+	Path     string   `json:"path"`
+	Policies []string `json:"policies"`
+}
+
+// Authorizer 声明式路由授权，记录每条路由的策略供审计
+type Authorizer struct {
+	log         logger.Logger
+	mu          sync.RWMutex
+	routes      map[string]RoutePolicy
+	annotations map[string]any
+}
+
+func NewAuthorizer(log logger.Logger) *Authorizer {
+	return &Authorizer{
+		log:         log,
+		routes:      make(map[string]RoutePolicy),
+		annotations: make(map[string]any),
+	}
+}
+
+// Require 返回校验请求主体的中间件，需全部策略通过
+func (a *Authorizer) Require(policies ...Policy) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		principal, _ := reqctx.PrincipalFrom(ctx.Request.Context())
+		for _, p := range policies {
+			if !p.Allow(ctx, principal) {
+				a.log.Warn(ctx, "无访问权限",
+					logger.StringAny("url", ctx.Request.URL.Path),
+					logger.StringAny("policy", p.String()),
+				)
+				response.Error(ctx, errs.Forbidden)
+				return
+			}
+		}
+		ctx.Next()
+	}
+}
+
+// Group 包装路由组，组内路由继承组策略
+func (a *Authorizer) Group(group *gin.RouterGroup, policies ...Policy) *Routes {
+	return &Routes{authz: a, group: group, policies: policies}
+}
+
+// Audit 合并已注册路由与授权策略，未声明策略的路由 Policies 为空
+func (a *Authorizer) Audit(routes gin.RoutesInfo) []RoutePolicy {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	res := make([]RoutePolicy, 0, len(routes))
+	for _, r := range routes {
+		rp, ok := a.routes[r.Method+" "+r.Path]
+		if !ok {
+			rp = RoutePolicy{Method: r.Method, Path: r.Path, Policies: []string{}}
+		}
+		res = append(res, rp)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Path != res[j].Path {
+			return res[i].Path < res[j].Path
+		}
+		return res[i].Method < res[j].Method
+	})
+	return res
+}
+
+// Annotation 返回注册路由时通过 Routes.Annotate 附加的描述信息
+func (a *Authorizer) Annotation(method, fullPath string) (any, bool) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	v, ok := a.annotations[method+" "+fullPath]
+	return v, ok
+}
+
+func (a *Authorizer) record(method, fullPath string, policies []Policy, annotation any) {
+	names := make([]string, 0, len(policies))
+	for _, p := range policies {
+		names = append(names, p.String())
+	}
+	key := method + " " + fullPath
+	a.mu.Lock()
+	a.routes[key] = RoutePolicy{Method: method, Path: fullPath, Policies: names}
+	if annotation != nil {
+		a.annotations[key] = annotation
+	}
+	a.mu.Unlock()
+}
+
+// Routes 带授权策略的路由组
+type Routes struct {
+	authz      *Authorizer
+	group      *gin.RouterGroup
+	policies   []Policy
+	annotation any
+	after      []gin.HandlerFunc // 授权通过后执行的中间件
+}
+
+// Group 创建子路由组，继承当前策略并追加新策略
+func (r *Routes) Group(relativePath string, policies ...Policy) *Routes {
+	return &Routes{
+		authz:    r.authz,
+		group:    r.group.Group(relativePath),
+		policies: append(append([]Policy(nil), r.policies...), policies...),
+		after:    r.after,
+	}
+}
+
+// With 为接下来注册的路由追加策略，不创建新的路由组
+func (r *Routes) With(policies ...Policy) *Routes {
+	return &Routes{
+		authz:      r.authz,
+		group:      r.group,
+		policies:   append(append([]Policy(nil), r.policies...), policies...),
+		annotation: r.annotation,
+		after:      r.after,
+	}
+}
+
+// Annotate 为接下来注册的路由附加描述信息，如请求与响应类型，不被子路由组继承
+func (r *Routes) Annotate(annotation any) *Routes {
+	return &Routes{
+		authz:      r.authz,
+		group:      r.group,
+		policies:   r.policies,
+		annotation: annotation,
+		after:      r.after,
+	}
+}
+
+// Use 追加在路由授权之后执行的中间件，返回新的路由组
+// 会直接输出已保存响应的中间件(如幂等重放)需放在这里，避免绕过授权
+func (r *Routes) Use(handlers ...gin.HandlerFunc) *Routes {
+	return &Routes{
+		authz:      r.authz,
+		group:      r.group,
+		policies:   r.policies,
+		annotation: r.annotation,
+		after:      append(append([]gin.HandlerFunc(nil), r.after...), handlers...),
+	}
+}
+
+// Handle 注册路由，执行顺序: 组中间件 -> 授权策略 -> Use 追加的中间件 -> handlers
+func (r *Routes) Handle(method, relativePath string, handlers ...gin.HandlerFunc) {
+	fullPath := path.Join(r.group.BasePath(), relativePath)
+	r.authz.record(method, fullPath, r.policies, r.annotation)
+	chain := make([]gin.HandlerFunc, 0, 1+len(r.after)+len(handlers))
+	if len(r.policies) > 0 {
+		chain = append(chain, r.authz.Require(r.policies...))
+	}
+	chain = append(chain, r.after...)
+	r.group.Handle(method, relativePath, append(chain, handlers...)...)
+}
+
+func (r *Routes) GET(relativePath string, handlers ...gin.HandlerFunc) {
+	r.Handle(http.MethodGet, relativePath, handlers...)
+}
+
+func (r *Routes) POST(relativePath string, handlers ...gin.HandlerFunc) {
+	r.Handle(http.MethodPost, relativePath, handlers...)
+}
+
+func (r *Routes) PUT(relativePath string, handlers ...gin.HandlerFunc) {
+	r.Handle(http.MethodPut, relativePath, handlers...)
+}
+
+func (r *Routes) PATCH(relativePath string, handlers ...gin.HandlerFunc) {
+	r.Handle(http.MethodPatch, relativePath, handlers...)
+}
+
+func (r *Routes) DELETE(relativePath string, handlers ...gin.HandlerFunc) {
+	r.Handle(http.MethodDelete, relativePath, handlers...)
+}
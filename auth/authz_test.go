@@ -0,0 +1,92 @@
+package auth
+
+import (
+	"go-wire/logger"
+	"go-wire/reqctx"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestAuthorizerRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	var principal *Principal
+	engine.Use(func(ctx *gin.Context) {
+		if principal != nil {
+			ctx.Request = ctx.Request.WithContext(reqctx.WithPrincipal(ctx.Request.Context(), principal))
+		}
+	})
+	authz := NewAuthorizer(logger.NewNop())
+	routes := authz.Group(engine.Group("/api"), Authenticated)
+	ok := func(ctx *gin.Context) { ctx.Status(http.StatusOK) }
+	routes.GET("/read", ok)
+	routes.With(RequireScopes("api:write")).POST("/write", ok)
+	routes.Group("/admin", RequireRoles("admin", "ops")).GET("/stats", ok)
+	engine.GET("/health", ok)
+
+	cases := []struct {
+		name      string
+		principal *Principal
+		method    string
+		path      string
+		code      int
+	}{
+		{"匿名", nil, http.MethodGet, "/api/read", http.StatusForbidden},
+		{"已认证", &Principal{ID: "u1"}, http.MethodGet, "/api/read", http.StatusOK},
+		{"缺少 scope", &Principal{ID: "u1", Scopes: []string{"api:read"}}, http.MethodPost, "/api/write", http.StatusForbidden},
+		{"具备 scope", &Principal{ID: "u1", Scopes: []string{"api:write"}}, http.MethodPost, "/api/write", http.StatusOK},
+		{"缺少角色", &Principal{ID: "u1", Roles: []string{"user"}}, http.MethodGet, "/api/admin/stats", http.StatusForbidden},
+		{"具备任一角色", &Principal{ID: "u1", Roles: []string{"ops"}}, http.MethodGet, "/api/admin/stats", http.StatusOK},
+		{"未声明策略", nil, http.MethodGet, "/health", http.StatusOK},
+	}
+	for _, c := range cases {
+		principal = c.principal
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, httptest.NewRequest(c.method, c.path, nil))
+		if w.Code != c.code {
+			t.Errorf("%s: %s %s 返回 %d，期望 %d", c.name, c.method, c.path, w.Code, c.code)
+		}
+	}
+
+	audit := authz.Audit(engine.Routes())
+	policies := make(map[string][]string, len(audit))
+	for _, rp := range audit {
+		policies[rp.Method+" "+rp.Path] = rp.Policies
+	}
+	if got := policies["GET /api/admin/stats"]; len(got) != 2 || got[1] != "roles:admin|ops" {
+		t.Errorf("审计策略: %v", got)
+	}
+	if got, ok := policies["GET /health"]; !ok || len(got) != 0 {
+		t.Errorf("未声明策略的路由: %v %v", got, ok)
+	}
+}
+
+// Use 追加的中间件在授权通过后才执行
+func TestRoutesUseAfterAuthorization(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	authz := NewAuthorizer(logger.NewNop())
+	var order []string
+	routes := authz.Group(engine.Group("/"), Authenticated).Use(func(ctx *gin.Context) { order = append(order, "after") })
+	routes.GET("/read", func(ctx *gin.Context) {
+		order = append(order, "handler")
+		ctx.Status(http.StatusOK)
+	})
+
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/read", nil))
+	if w.Code != http.StatusForbidden || len(order) != 0 {
+		t.Fatalf("授权失败: status=%d order=%v", w.Code, order)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/read", nil)
+	req = req.WithContext(reqctx.WithPrincipal(req.Context(), &Principal{ID: "u1"}))
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, req)
+	if w.Code != http.StatusOK || len(order) != 2 || order[0] != "after" {
+		t.Fatalf("授权通过: status=%d order=%v", w.Code, order)
+	}
+}
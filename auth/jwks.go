@@ -0,0 +1,294 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go-wire/config"
+	"math/big"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const defaultJWKSReload = time.Minute
+
+// verifyKey 验签密钥，alg 与密钥绑定以防算法混淆
+type verifyKey struct {
+	alg string
+	key any
+}
+
+// keySet 静态密钥与本地 JWKS 文件合并的密钥集，JWKS 文件变更后自动重载
+type keySet struct {
+	static map[string]verifyKey
+
+	file      string
+	interval  time.Duration
+	mu        sync.RWMutex
+	jwks      map[string]verifyKey
+	modTime   time.Time
+	lastCheck time.Time
+}
+
+func newKeySet(cfg config.JWTConfig) (*keySet, error) {
+	ks := &keySet{
+		static:   make(map[string]verifyKey, len(cfg.Keys)+1),
+		file:     cfg.JWKSFile,
+		interval: cfg.JWKSReload,
+	}
+	if ks.interval <= 0 {
+		ks.interval = defaultJWKSReload
+	}
+	if cfg.Secret != "" {
+		ks.static[""] = verifyKey{alg: jwt.SigningMethodHS256.Alg(), key: []byte(cfg.Secret)}
+	}
+	for _, k := range cfg.Keys {
+		key, err := loadVerifyKey(k)
+		if err != nil {
+			return nil, fmt.Errorf("JWT 密钥 [%s] 加载失败: %w", k.Kid, err)
+		}
+		ks.static[k.Kid] = key
+	}
+	if ks.file != "" {
+		if err := ks.reload(time.Now()); err != nil {
+			return nil, err
+		}
+	}
+	return ks, nil
+}
+
+// Lookup 按 kid 选择密钥，token 未携带 kid 时仅在唯一密钥时使用该密钥
+func (ks *keySet) Lookup(kid string) (verifyKey, bool) {
+	if key, ok := ks.static[kid]; ok {
+		return key, true
+	}
+	ks.refresh()
+
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+	if key, ok := ks.jwks[kid]; ok {
+		return key, true
+	}
+	if kid == "" && len(ks.static)+len(ks.jwks) == 1 {
+		for _, key := range ks.static {
+			return key, true
+		}
+		for _, key := range ks.jwks {
+			return key, true
+		}
+	}
+	return verifyKey{}, false
+}
+
+// refresh 超过检查间隔且文件有变更时重载 JWKS，失败保留旧密钥
+func (ks *keySet) refresh() {
+	if ks.file == "" {
+		return
+	}
+	now := time.Now()
+	ks.mu.RLock()
+	due := now.Sub(ks.lastCheck) >= ks.interval
+	ks.mu.RUnlock()
+	if due {
+		_ = ks.reload(now)
+	}
+}
+
+func (ks *keySet) reload(now time.Time) error {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+	ks.lastCheck = now
+
+	info, err := os.Stat(ks.file)
+	if err != nil {
+		return fmt.Errorf("JWKS 文件读取失败: %w", err)
+	}
+	if info.ModTime().Equal(ks.modTime) {
+		return nil
+	}
+	data, err := os.ReadFile(ks.file)
+	if err != nil {
+		return fmt.Errorf("JWKS 文件读取失败: %w", err)
+	}
+	keys, err := parseJWKS(data)
+	if err != nil {
+		return err
+	}
+	ks.jwks = keys
+	ks.modTime = info.ModTime()
+	return nil
+}
+
+// loadVerifyKey 加载配置中的静态密钥
+func loadVerifyKey(k config.JWTKey) (verifyKey, error) {
+	alg := k.Alg
+	if alg == "" {
+		alg = jwt.SigningMethodHS256.Alg()
+	}
+	if alg == jwt.SigningMethodHS256.Alg() {
+		if k.Secret == "" {
+			return verifyKey{}, errors.New("缺少 secret")
+		}
+		return verifyKey{alg: alg, key: []byte(k.Secret)}, nil
+	}
+
+	data, err := os.ReadFile(k.PublicKey)
+	if err != nil {
+		return verifyKey{}, err
+	}
+	var key any
+	switch alg {
+	case jwt.SigningMethodRS256.Alg():
+		key, err = jwt.ParseRSAPublicKeyFromPEM(data)
+	case jwt.SigningMethodES256.Alg():
+		key, err = jwt.ParseECPublicKeyFromPEM(data)
+	case jwt.SigningMethodEdDSA.Alg():
+		key, err = jwt.ParseEdPublicKeyFromPEM(data)
+	default:
+		err = fmt.Errorf("不支持的算法 %s", alg)
+	}
+	if err != nil {
+		return verifyKey{}, err
+	}
+	return verifyKey{alg: alg, key: key}, nil
+}
+
+// loadSigningKey 加载续期签发使用的私钥
+func loadSigningKey(k config.JWTKey) (jwt.SigningMethod, any, error) {
+	alg := k.Alg
+	if alg == "" || alg == jwt.SigningMethodHS256.Alg() {
+		return jwt.SigningMethodHS256, []byte(k.Secret), nil
+	}
+
+	data, err := os.ReadFile(k.PrivateKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	var key crypto.PrivateKey
+	switch alg {
+	case jwt.SigningMethodRS256.Alg():
+		key, err = jwt.ParseRSAPrivateKeyFromPEM(data)
+	case jwt.SigningMethodES256.Alg():
+		key, err = jwt.ParseECPrivateKeyFromPEM(data)
+	case jwt.SigningMethodEdDSA.Alg():
+		key, err = jwt.ParseEdPrivateKeyFromPEM(data)
+	default:
+		err = fmt.Errorf("不支持的算法 %s", alg)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return jwt.GetSigningMethod(alg), key, nil
+}
+
+type jwk struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+	Crv string `json:"crv"`
+	K   string `json:"k"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// parseJWKS 解析 JWKS，跳过非签名用途的密钥
+func parseJWKS(data []byte) (map[string]verifyKey, error) {
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("JWKS 解析失败: %w", err)
+	}
+	keys := make(map[string]verifyKey, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		key, err := k.verifyKey()
+		if err != nil {
+			return nil, fmt.Errorf("JWK [%s] 解析失败: %w", k.Kid, err)
+		}
+		keys[k.Kid] = key
+	}
+	return keys, nil
+}
+
+func (k jwk) verifyKey() (verifyKey, error) {
+	switch k.Kty {
+	case "oct":
+		secret, err := base64.RawURLEncoding.DecodeString(k.K)
+		if err != nil {
+			return verifyKey{}, err
+		}
+		return verifyKey{alg: k.algOr(jwt.SigningMethodHS256.Alg()), key: secret}, nil
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return verifyKey{}, err
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return verifyKey{}, err
+		}
+		return verifyKey{
+			alg: k.algOr(jwt.SigningMethodRS256.Alg()),
+			key: &rsa.PublicKey{N: n, E: int(e.Int64())},
+		}, nil
+	case "EC":
+		if k.Crv != "P-256" {
+			return verifyKey{}, fmt.Errorf("不支持的曲线 %s", k.Crv)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return verifyKey{}, err
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return verifyKey{}, err
+		}
+		return verifyKey{
+			alg: k.algOr(jwt.SigningMethodES256.Alg()),
+			key: &ecdsa.PublicKey{Curve: elliptic.P256(), X: x, Y: y},
+		}, nil
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			return verifyKey{}, fmt.Errorf("不支持的曲线 %s", k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return verifyKey{}, err
+		}
+		if len(x) != ed25519.PublicKeySize {
+			return verifyKey{}, errors.New("Ed25519 公钥长度错误")
+		}
+		return verifyKey{alg: k.algOr(jwt.SigningMethodEdDSA.Alg()), key: ed25519.PublicKey(x)}, nil
+	}
+	return verifyKey{}, fmt.Errorf("不支持的密钥类型 %s", k.Kty)
+}
+
+func (k jwk) algOr(def string) string {
+	if k.Alg != "" {
+		return k.Alg
+	}
+	return def
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(b), nil
+}
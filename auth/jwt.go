@@ -0,0 +1,224 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"go-wire/config"
+	"maps"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const JWTName = "jwt"
+
+// jwtAuthenticator Bearer JWT 认证，支持 HS256/RS256/ES256/EdDSA 与滑动续期
+type jwtAuthenticator struct {
+	keys    *keySet
+	parser  *jwt.Parser
+	claims  config.JWTClaimsConfig
+	refresh config.JWTRefreshConfig
+
+	signMethod jwt.SigningMethod
+	signKey    any
+	signKid    string
+}
+
+func newJWTAuthenticator(cfg config.JWTConfig) (*jwtAuthenticator, error) {
+	algorithms := cfg.Algorithms
+	if len(algorithms) == 0 {
+		algorithms = []string{jwt.SigningMethodHS256.Alg()}
+	}
+	opts := []jwt.ParserOption{
+		jwt.WithValidMethods(algorithms),
+		jwt.WithLeeway(cfg.Leeway),
+		jwt.WithExpirationRequired(),
+	}
+	if cfg.Issuer != "" {
+		opts = append(opts, jwt.WithIssuer(cfg.Issuer))
+	}
+	if cfg.Audience != "" {
+		opts = append(opts, jwt.WithAudience(cfg.Audience))
+	}
+
+	keys, err := newKeySet(cfg)
+	if err != nil {
+		return nil, err
+	}
+	a := &jwtAuthenticator{
+		keys:    keys,
+		parser:  jwt.NewParser(opts...),
+		claims:  cfg.Claims,
+		refresh: cfg.Refresh,
+	}
+	if a.claims.UserID == "" {
+		a.claims.UserID = "sub"
+	}
+	if a.claims.Roles == "" {
+		a.claims.Roles = "roles"
+	}
+	if a.claims.Scopes == "" {
+		a.claims.Scopes = "scope"
+	}
+
+	if cfg.Refresh.Enabled {
+		if cfg.Refresh.TTL <= 0 {
+			return nil, errors.New("JWT 续期有效期 ttl 需大于 0")
+		}
+		if cfg.Refresh.Threshold <= 0 || cfg.Refresh.Threshold >= cfg.Refresh.TTL {
+			return nil, errors.New("JWT 续期阈值 threshold 需大于 0 且小于 ttl")
+		}
+		if err = a.loadSigningKey(cfg); err != nil {
+			return nil, fmt.Errorf("JWT 续期密钥加载失败: %w", err)
+		}
+	}
+	return a, nil
+}
+
+func (a *jwtAuthenticator) loadSigningKey(cfg config.JWTConfig) error {
+	if cfg.Refresh.Kid == "" {
+		if cfg.Secret == "" {
+			return errors.New("未配置 secret")
+		}
+		a.signMethod, a.signKey = jwt.SigningMethodHS256, []byte(cfg.Secret)
+		return nil
+	}
+	for _, k := range cfg.Keys {
+		if k.Kid == cfg.Refresh.Kid {
+			method, key, err := loadSigningKey(k)
+			if err != nil {
+				return err
+			}
+			a.signMethod, a.signKey, a.signKid = method, key, k.Kid
+			return nil
+		}
+	}
+	return fmt.Errorf("密钥 [%s] 不存在", cfg.Refresh.Kid)
+}
+
+func (a *jwtAuthenticator) Name() string {
+	return JWTName
+}
+
+func (a *jwtAuthenticator) Authenticate(r *http.Request) (*Principal, error) {
+	raw, ok := bearerToken(r)
+	if !ok {
+		return nil, ErrNoCredentials
+	}
+
+	claims := jwt.MapClaims{}
+	token, err := a.parser.ParseWithClaims(raw, claims, a.keyFunc)
+	if err != nil {
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return nil, ErrExpiredCredentials
+		}
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCredentials, err)
+	}
+
+	id, _ := claimValue(claims, a.claims.UserID).(string)
+	return &Principal{
+		ID:          id,
+		Type:        JWTName,
+		Roles:       stringList(claimValue(claims, a.claims.Roles)),
+		Scopes:      stringList(claimValue(claims, a.claims.Scopes)),
+		Claims:      claims,
+		Refreshable: a.signedBySelf(token),
+	}, nil
+}
+
+// signedBySelf token 由续期签发密钥签名，JWKS 与其他静态密钥签发的 token 不续期
+func (a *jwtAuthenticator) signedBySelf(token *jwt.Token) bool {
+	if a.signMethod == nil || token.Method.Alg() != a.signMethod.Alg() {
+		return false
+	}
+	kid, _ := token.Header["kid"].(string)
+	if kid != a.signKid {
+		return false
+	}
+	_, ok := a.keys.static[kid]
+	return ok
+}
+
+// keyFunc 按 token 头部 kid 选择密钥，并校验算法与密钥匹配
+func (a *jwtAuthenticator) keyFunc(token *jwt.Token) (any, error) {
+	kid, _ := token.Header["kid"].(string)
+	key, ok := a.keys.Lookup(kid)
+	if !ok {
+		return nil, fmt.Errorf("密钥 [%s] 不存在", kid)
+	}
+	if token.Method.Alg() != key.alg {
+		return nil, fmt.Errorf("算法 %s 与密钥 [%s] 不匹配", token.Method.Alg(), kid)
+	}
+	return key.key, nil
+}
+
+// Refresh 剩余有效期低于阈值时签发新 token，仅续期本服务签发的 token
+func (a *jwtAuthenticator) Refresh(p *Principal) (string, time.Time, bool, error) {
+	if !a.refresh.Enabled || !p.Refreshable || p.Claims == nil {
+		return "", time.Time{}, false, nil
+	}
+	claims := jwt.MapClaims(p.Claims)
+	exp, err := claims.GetExpirationTime()
+	if err != nil || exp == nil || time.Until(exp.Time) > a.refresh.Threshold {
+		return "", time.Time{}, false, err
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(a.refresh.TTL)
+	next := maps.Clone(claims)
+	next["iat"] = now.Unix()
+	next["nbf"] = now.Unix()
+	next["exp"] = expiresAt.Unix()
+
+	token := jwt.NewWithClaims(a.signMethod, next)
+	if a.signKid != "" {
+		token.Header["kid"] = a.signKid
+	}
+	signed, err := token.SignedString(a.signKey)
+	if err != nil {
+		return "", time.Time{}, false, err
+	}
+	return signed, expiresAt, true, nil
+}
+
+// bearerToken 读取 Authorization: Bearer <token>
+func bearerToken(r *http.Request) (string, bool) {
+	header := r.Header.Get("Authorization")
+	scheme, token, ok := strings.Cut(header, " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") || token == "" {
+		return "", false
+	}
+	return strings.TrimSpace(token), true
+}
+
+// claimValue 按 a.b 形式的路径读取嵌套声明
+func claimValue(claims map[string]any, path string) any {
+	var cur any = claims
+	for _, key := range strings.Split(path, ".") {
+		m, ok := cur.(map[string]any)
+		if !ok {
+			return nil
+		}
+		cur = m[key]
+	}
+	return cur
+}
+
+// stringList 兼容字符串数组与空格分隔字符串两种声明格式
+func stringList(v any) []string {
+	switch val := v.(type) {
+	case string:
+		return strings.Fields(val)
+	case []any:
+		res := make([]string, 0, len(val))
+		for _, item := range val {
+			if s, ok := item.(string); ok {
+				res = append(res, s)
+			}
+		}
+		return res
+	}
+	return nil
+}
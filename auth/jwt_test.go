@@ -0,0 +1,98 @@
+package auth
+
+import (
+	"errors"
+	"go-wire/config"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func signHS256(t *testing.T, kid, secret string, ttl time.Duration) string {
+	t.Helper()
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub":   "u1",
+		"scope": "api:read api:write",
+		"exp":   time.Now().Add(ttl).Unix(),
+	})
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	signed, err := token.SignedString([]byte(secret))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed
+}
+
+func jwtConfig() config.JWTConfig {
+	return config.JWTConfig{
+		Secret:  "self",
+		Keys:    []config.JWTKey{{Kid: "partner", Alg: "HS256", Secret: "partner"}},
+		Refresh: config.JWTRefreshConfig{Enabled: true, Threshold: 10 * time.Minute, TTL: time.Hour},
+	}
+}
+
+func TestJWTAuthenticate(t *testing.T) {
+	a, err := newJWTAuthenticator(jwtConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := a.Authenticate(newRequest("/", "Authorization", "Bearer "+signHS256(t, "", "self", time.Hour)))
+	if err != nil || p.ID != "u1" || !p.HasScope("api:write") {
+		t.Fatalf("principal=%+v err=%v", p, err)
+	}
+	if _, err = a.Authenticate(newRequest("/", "Authorization", "Bearer "+signHS256(t, "", "wrong", time.Hour))); err == nil {
+		t.Fatal("密钥错误的 token 应认证失败")
+	}
+	if _, err = a.Authenticate(newRequest("/", "Authorization", "Bearer "+signHS256(t, "", "self", -time.Minute))); !errors.Is(err, ErrExpiredCredentials) {
+		t.Fatalf("过期 token: err=%v", err)
+	}
+}
+
+func TestJWTRefresh(t *testing.T) {
+	a, err := newJWTAuthenticator(jwtConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	refresh := func(raw string) bool {
+		p, err := a.Authenticate(newRequest("/", "Authorization", "Bearer "+raw))
+		if err != nil {
+			t.Fatal(err)
+		}
+		token, expiresAt, ok, err := a.Refresh(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ok && (token == "" || time.Until(expiresAt) < 50*time.Minute) {
+			t.Fatalf("新 token 有效期不足: %v", expiresAt)
+		}
+		return ok
+	}
+
+	if !refresh(signHS256(t, "", "self", 5*time.Minute)) {
+		t.Fatal("临近过期的 token 应续期")
+	}
+	if refresh(signHS256(t, "", "self", time.Hour)) {
+		t.Fatal("未到续期阈值的 token 不应续期")
+	}
+	// 其他密钥签发的 token 可以认证，但不由本服务重新签发
+	if refresh(signHS256(t, "partner", "partner", 5*time.Minute)) {
+		t.Fatal("非本服务签发的 token 不应续期")
+	}
+}
+
+func TestJWTRefreshConfig(t *testing.T) {
+	for name, refresh := range map[string]config.JWTRefreshConfig{
+		"ttl 为 0":         {Enabled: true, Threshold: time.Minute},
+		"threshold 为 0":   {Enabled: true, TTL: time.Hour},
+		"threshold ≥ ttl": {Enabled: true, Threshold: time.Hour, TTL: time.Hour},
+	} {
+		cfg := jwtConfig()
+		cfg.Refresh = refresh
+		if _, err := newJWTAuthenticator(cfg); err == nil {
+			t.Errorf("%s: 应返回配置错误", name)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"go-wire/config"
+	"go-wire/logger"
+	"go-wire/redis"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Manager 管理已注册的认证器，并按路由组组装认证链
+type Manager struct {
+	log            logger.Logger
+	authenticators map[string]Authenticator
+	defaults       []string
+	groups         []config.AuthGroup
+}
+
+func NewManager(cfg *config.Config, log logger.Logger, rdb *redis.Redis) (*Manager, error) {
+	m := &Manager{
+		log:            log,
+		authenticators: make(map[string]Authenticator),
+		defaults:       cfg.Auth.Authenticators,
+		groups:         sortGroups(cfg.Auth.Groups),
+	}
+
+	apiKey, err := newAPIKeyAuthenticator(cfg)
+	if err != nil {
+		return nil, err
+	}
+	m.Register(apiKey)
+	m.Register(anonymousAuthenticator{})
+	if jwtCfg := cfg.Auth.JWT; jwtCfg.Secret != "" || len(jwtCfg.Keys) > 0 || jwtCfg.JWKSFile != "" {
+		jwtAuth, err := newJWTAuthenticator(jwtCfg)
+		if err != nil {
+			return nil, err
+		}
+		m.Register(jwtAuth)
+	}
+	if len(cfg.Auth.HMAC.Clients) > 0 {
+		nonces, err := rdb.Client(cfg.Auth.HMAC.Redis)
+		if err != nil {
+			return nil, fmt.Errorf("HMAC 认证初始化失败: %w", err)
+		}
+		m.Register(newHMACAuthenticator(cfg.Auth.HMAC, nonces))
+	}
+
+	// 未配置认证链时，有 API Key 则校验 API Key，否则允许匿名访问
+	if len(m.defaults) == 0 {
+		m.defaults = []string{AnonymousName}
+		if len(apiKey.keys) > 0 {
+			m.defaults = []string{APIKeyName}
+		}
+	}
+
+	if err = m.validate(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Register 注册认证器，同名覆盖，需在服务启动前调用
+func (m *Manager) Register(a Authenticator) {
+	m.authenticators[a.Name()] = a
+}
+
+// Authenticate 按请求路径匹配认证链并依次尝试
+func (m *Manager) Authenticate(r *http.Request) (*Principal, error) {
+	for _, name := range m.chain(r.URL.Path) {
+		a, ok := m.authenticators[name]
+		if !ok {
+			return nil, fmt.Errorf("认证器 [%s] 未注册", name)
+		}
+		p, err := a.Authenticate(r)
+		if errors.Is(err, ErrNoCredentials) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return p, nil
+	}
+	return nil, ErrNoCredentials
+}
+
+// Refresh 由签发该主体的认证器判断是否需要续期
+func (m *Manager) Refresh(p *Principal) (string, time.Time, bool, error) {
+	if r, ok := m.authenticators[p.Type].(Refresher); ok {
+		return r.Refresh(p)
+	}
+	return "", time.Time{}, false, nil
+}
+
+// chain 返回路径对应的认证链，路由组按前缀最长匹配
+func (m *Manager) chain(path string) []string {
+	for _, g := range m.groups {
+		if path == g.Prefix || strings.HasPrefix(path, strings.TrimSuffix(g.Prefix, "/")+"/") {
+			return g.Authenticators
+		}
+	}
+	return m.defaults
+}
+
+// validate 启动时校验认证链引用的认证器都已注册
+func (m *Manager) validate() error {
+	chains := [][]string{m.defaults}
+	for _, g := range m.groups {
+		chains = append(chains, g.Authenticators)
+	}
+	for _, chain := range chains {
+		for _, name := range chain {
+			if _, ok := m.authenticators[name]; !ok {
+				return fmt.Errorf("认证器 [%s] 未注册", name)
+			}
+		}
+	}
+	return nil
+}
+
+// sortGroups 复制路由组并按前缀长度降序排列
+func sortGroups(groups []config.AuthGroup) []config.AuthGroup {
+	res := append([]config.AuthGroup(nil), groups...)
+	sort.SliceStable(res, func(i, j int) bool {
+		return len(res[i].Prefix) > len(res[j].Prefix)
+	})
+	return res
+}
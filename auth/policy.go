@@ -0,0 +1,62 @@
+package auth
+
+import (
+	"context"
+	"strings"
+)
+
+// Policy 授权策略，Roles 满足任一即可，Scopes 需全部具备，Check 为自定义判断
+type Policy struct {
+	Name   string
+	Roles  []string
+	Scopes []string
+	Check  func(ctx context.Context, p *Principal) bool
+}
+
+// RequireRoles 需具备任一角色
+func RequireRoles(roles ...string) Policy {
+	return Policy{Name: "roles:" + strings.Join(roles, "|"), Roles: roles}
+}
+
+// RequireScopes 需具备全部 scope
+func RequireScopes(scopes ...string) Policy {
+	return Policy{Name: "scopes:" + strings.Join(scopes, ","), Scopes: scopes}
+}
+
+// RequireFunc 自定义策略
+func RequireFunc(name string, check func(ctx context.Context, p *Principal) bool) Policy {
+	return Policy{Name: name, Check: check}
+}
+
+// Authenticated 拒绝匿名访问
+var Authenticated = RequireFunc("authenticated", func(_ context.Context, p *Principal) bool {
+	return !p.Anonymous()
+})
+
+func (p Policy) Allow(ctx context.Context, principal *Principal) bool {
+	if principal == nil {
+		return false
+	}
+	if len(p.Roles) > 0 {
+		matched := false
+		for _, role := range p.Roles {
+			if principal.HasRole(role) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	for _, scope := range p.Scopes {
+		if !principal.HasScope(scope) {
+			return false
+		}
+	}
+	return p.Check == nil || p.Check(ctx, principal)
+}
+
+func (p Policy) String() string {
+	return p.Name
+}
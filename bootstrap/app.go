@@ -7,6 +7,7 @@ import (
 	"go-wire/config"
 	"go-wire/logger"
 	"go-wire/redis"
+	"go-wire/reqctx"
 	"net/http"
 	"os"
 	"os/signal"
@@ -48,7 +49,7 @@ func (app *App) Run() error {
 		MaxHeaderBytes: 1 << 20,
 	}
 
-	ctx := context.WithValue(context.Background(), "TraceID", fmt.Sprintf("main:date:%s", time.Now().Format("2006-01-02 15:04:05")))
+	ctx := reqctx.Background("main")
 	shutdownCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
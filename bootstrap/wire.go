@@ -4,6 +4,8 @@
 package bootstrap
 
 import (
+	"go-wire/auth"
+	"go-wire/cache"
 	"go-wire/config"
 	"go-wire/controller"
 	"go-wire/logger"
@@ -12,6 +14,7 @@ import (
 	"go-wire/router"
 	"go-wire/router/middleware"
 	"go-wire/service"
+	"go-wire/validation"
 
 	"github.com/google/wire"
 )
@@ -21,6 +24,9 @@ func InitApp() (*App, error) {
 		config.ProviderSet,
 		logger.ProviderSet,
 		redis.ProviderSet,
+		auth.ProviderSet,
+		validation.ProviderSet,
+		cache.ProviderSet,
 		repo.ProviderSet,
 		service.ProviderSet,
 		controller.ProviderSet,
@@ -0,0 +1,104 @@
+// Package cache HTTP 响应缓存，存储可选进程内 LRU 或 redis
+// 业务层写入数据后按标签失效相关缓存，如 cache.Invalidate(ctx, "test:"+id)
+package cache
+
+import (
+	"context"
+	"fmt"
+	"go-wire/config"
+	"go-wire/logger"
+	"go-wire/redis"
+	"net/http"
+	"time"
+
+	"github.com/google/wire"
+)
+
+var ProviderSet = wire.NewSet(NewCache)
+
+const defaultMaxEntries = 1000
+
+// Entry 缓存的响应
+type Entry struct {
+	Status   int           `json:"status"`
+	Header   http.Header   `json:"header,omitempty"`
+	Body     []byte        `json:"body,omitempty"`
+	ETag     string        `json:"etag"`
+	Tags     []string      `json:"tags,omitempty"`
+	StoredAt time.Time     `json:"storedAt"`
+	TTL      time.Duration `json:"ttl"`
+}
+
+// Age 已缓存时长
+func (e *Entry) Age() time.Duration {
+	return time.Since(e.StoredAt)
+}
+
+// Remaining 剩余有效时长
+func (e *Entry) Remaining() time.Duration {
+	return max(e.TTL-e.Age(), 0)
+}
+
+// Store 缓存存储，标签用于批量失效
+type Store interface {
+	Get(ctx context.Context, key string) (*Entry, bool, error)
+	Set(ctx context.Context, key string, e *Entry) error
+	Invalidate(ctx context.Context, tags ...string) error
+}
+
+// Cache 响应缓存，存储异常时按未命中处理
+type Cache struct {
+	store Store
+	log   logger.Logger
+}
+
+func NewCache(cfg *config.Config, log logger.Logger, rdb *redis.Redis) (*Cache, error) {
+	c := cfg.Cache
+	var store Store
+	switch c.Driver {
+	case "", "memory":
+		maxEntries := c.MaxEntries
+		if maxEntries <= 0 {
+			maxEntries = defaultMaxEntries
+		}
+		store = newMemoryStore(maxEntries)
+	case "redis":
+		client, err := rdb.Client(c.Redis)
+		if err != nil {
+			return nil, fmt.Errorf("响应缓存初始化失败: %w", err)
+		}
+		store = &redisStore{client: client}
+	default:
+		return nil, fmt.Errorf("不支持的缓存存储: %s", c.Driver)
+	}
+	return &Cache{store: store, log: log}, nil
+}
+
+// Get 读取未过期的缓存
+func (c *Cache) Get(ctx context.Context, key string) (*Entry, bool) {
+	e, ok, err := c.store.Get(ctx, key)
+	if err != nil {
+		c.log.Warn(ctx, "读取响应缓存失败", logger.Error(err))
+		return nil, false
+	}
+	return e, ok
+}
+
+// Set 写入缓存，过期时间取 e.TTL
+func (c *Cache) Set(ctx context.Context, key string, e *Entry) {
+	if err := c.store.Set(ctx, key, e); err != nil {
+		c.log.Warn(ctx, "写入响应缓存失败", logger.Error(err))
+	}
+}
+
+// Invalidate 删除带有任一标签的缓存
+func (c *Cache) Invalidate(ctx context.Context, tags ...string) error {
+	if len(tags) == 0 {
+		return nil
+	}
+	if err := c.store.Invalidate(ctx, tags...); err != nil {
+		c.log.Error(ctx, "响应缓存失效失败", logger.StringAny("tags", tags), logger.Error(err))
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"sync"
+)
+
+// memoryStore 进程内 LRU，多副本部署时失效仅作用于当前进程
+type memoryStore struct {
+	mu         sync.Mutex
+	maxEntries int
+	ll         *list.List
+	items      map[string]*list.Element
+	tags       map[string]map[string]struct{} // 标签 -> 缓存键
+}
+
+type memoryItem struct {
+	key   string
+	entry *Entry
+}
+
+func newMemoryStore(maxEntries int) *memoryStore {
+	return &memoryStore{
+		maxEntries: maxEntries,
+		ll:         list.New(),
+		items:      make(map[string]*list.Element),
+		tags:       make(map[string]map[string]struct{}),
+	}
+}
+
+func (s *memoryStore) Get(_ context.Context, key string) (*Entry, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	elem, ok := s.items[key]
+	if !ok {
+		return nil, false, nil
+	}
+	item := elem.Value.(*memoryItem)
+	if item.entry.Remaining() <= 0 {
+		s.remove(elem)
+		return nil, false, nil
+	}
+	s.ll.MoveToFront(elem)
+	return item.entry, true, nil
+}
+
+func (s *memoryStore) Set(_ context.Context, key string, e *Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if elem, ok := s.items[key]; ok {
+		s.remove(elem)
+	}
+	s.items[key] = s.ll.PushFront(&memoryItem{key: key, entry: e})
+	for _, tag := range e.Tags {
+		keys, ok := s.tags[tag]
+		if !ok {
+			keys = make(map[string]struct{})
+			s.tags[tag] = keys
+		}
+		keys[key] = struct{}{}
+	}
+	for s.ll.Len() > s.maxEntries {
+		s.remove(s.ll.Back())
+	}
+	return nil
+}
+
+func (s *memoryStore) Invalidate(_ context.Context, tags ...string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, tag := range tags {
+		for key := range s.tags[tag] {
+			if elem, ok := s.items[key]; ok {
+				s.remove(elem)
+			}
+		}
+		delete(s.tags, tag)
+	}
+	return nil
+}
+
+// remove 删除缓存并清理标签索引，调用方需持有锁
+func (s *memoryStore) remove(elem *list.Element) {
+	item := s.ll.Remove(elem).(*memoryItem)
+	delete(s.items, item.key)
+	for _, tag := range item.entry.Tags {
+		if keys, ok := s.tags[tag]; ok {
+			delete(keys, item.key)
+			if len(keys) == 0 {
+				delete(s.tags, tag)
+			}
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const tagKeyPrefix = "cache:tag:"
+
+// redisStore 缓存以 JSON 保存，标签为保存缓存键的集合，集合过期时间不短于其中最长的缓存
+type redisStore struct {
+	client redis.UniversalClient
+}
+
+func (s *redisStore) Get(ctx context.Context, key string) (*Entry, bool, error) {
+	raw, err := s.client.Get(ctx, key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	var e Entry
+	if err := json.Unmarshal(raw, &e); err != nil {
+		return nil, false, err
+	}
+	return &e, true, nil
+}
+
+func (s *redisStore) Set(ctx context.Context, key string, e *Entry) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	pipe := s.client.Pipeline()
+	pipe.Set(ctx, key, data, e.TTL)
+	ttls := make([]*redis.DurationCmd, len(e.Tags))
+	for i, tag := range e.Tags {
+		pipe.SAdd(ctx, tagKeyPrefix+tag, key)
+		ttls[i] = pipe.PTTL(ctx, tagKeyPrefix+tag)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return err
+	}
+
+	pipe = s.client.Pipeline()
+	for i, tag := range e.Tags {
+		// 新建的集合 PTTL 为 -1
+		if ttl := ttls[i].Val(); ttl < e.TTL {
+			pipe.PExpire(ctx, tagKeyPrefix+tag, e.TTL)
+		}
+	}
+	if pipe.Len() == 0 {
+		return nil
+	}
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+// Invalidate 逐个标签删除缓存键，不使用脚本以兼容 cluster 跨槽
+func (s *redisStore) Invalidate(ctx context.Context, tags ...string) error {
+	for _, tag := range tags {
+		keys, err := s.client.SMembers(ctx, tagKeyPrefix+tag).Result()
+		if err != nil {
+			return err
+		}
+		pipe := s.client.Pipeline()
+		for _, key := range keys {
+			pipe.Del(ctx, key)
+		}
+		pipe.Del(ctx, tagKeyPrefix+tag)
+		if _, err := pipe.Exec(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
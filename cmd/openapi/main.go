@@ -0,0 +1,55 @@
+// openapi 生成接口文档，供 CI 比对接口变更
+//
+//	go run ./cmd/openapi -o openapi.json
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go-wire/auth"
+	"go-wire/controller"
+	"go-wire/logger"
+	"go-wire/openapi"
+	"os"
+
+	"github.com/gin-gonic/gin"
+)
+
+func main() {
+	out := flag.String("o", "", "输出文件，默认输出到标准输出")
+	title := flag.String("title", "go-wire", "文档标题")
+	version := flag.String("version", "1.0.0", "接口版本")
+	flag.Parse()
+
+	gin.SetMode(gin.ReleaseMode)
+	engine := gin.New()
+
+	// 仅注册路由，处理函数不会被调用，依赖可为空
+	log := logger.NewNop()
+	authz := auth.NewAuthorizer(log)
+	registrars := []controller.RouteRegistrar{
+		controller.NewApiController(nil, log, nil),
+	}
+	routes := authz.Group(engine.Group("/"))
+	for _, r := range registrars {
+		r.RegisterRoutes(routes)
+	}
+
+	doc := openapi.Build(openapi.Info{Title: *title, Version: *version}, engine.Routes(), authz)
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "文档序列化失败:", err)
+		os.Exit(1)
+	}
+	data = append(data, '\n')
+
+	if *out == "" {
+		_, _ = os.Stdout.Write(data)
+		return
+	}
+	if err = os.WriteFile(*out, data, 0o644); err != nil {
+		fmt.Fprintln(os.Stderr, "文档写入失败:", err)
+		os.Exit(1)
+	}
+}
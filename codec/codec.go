@@ -0,0 +1,116 @@
+package codec
+
+import (
+	"expvar"
+	"mime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	JSON     = "json"
+	MsgPack  = "msgpack"
+	Protobuf = "protobuf"
+	CBOR     = "cbor"
+)
+
+// Codec 请求体与响应体的编解码器
+type Codec interface {
+	Name() string
+	ContentType() string
+	Marshal(v any) ([]byte, error)
+	Unmarshal(data []byte, v any) error
+}
+
+var registry = struct {
+	sync.RWMutex
+	byName map[string]Codec
+	byType map[string]Codec
+}{byName: make(map[string]Codec), byType: make(map[string]Codec)}
+
+// metrics 按方向与编解码器统计次数，如 response.msgpack，通过 expvar 的 codec 变量输出
+var metrics = expvar.NewMap("codec")
+
+// Record 记录一次编解码，kind 为 request 或 response
+func Record(kind, name string) {
+	metrics.Add(kind+"."+name, 1)
+}
+
+// Register 注册编解码器，aliases 为额外识别的媒体类型，应在包初始化时调用
+func Register(c Codec, aliases ...string) {
+	registry.Lock()
+	defer registry.Unlock()
+	registry.byName[c.Name()] = c
+	for _, t := range append([]string{c.ContentType()}, aliases...) {
+		if mediaType, _, err := mime.ParseMediaType(t); err == nil {
+			registry.byType[mediaType] = c
+		}
+	}
+}
+
+// Lookup 按名称查找编解码器
+func Lookup(name string) (Codec, bool) {
+	registry.RLock()
+	defer registry.RUnlock()
+	c, ok := registry.byName[name]
+	return c, ok
+}
+
+// ForContentType 按 Content-Type 查找编解码器，忽略参数
+func ForContentType(contentType string) (Codec, bool) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil, false
+	}
+	registry.RLock()
+	defer registry.RUnlock()
+	c, ok := registry.byType[mediaType]
+	return c, ok
+}
+
+// Default 未能协商时使用的编解码器
+func Default() Codec {
+	c, _ := Lookup(JSON)
+	return c
+}
+
+// Negotiate 按 Accept 的权重选择编解码器，无匹配或未指定时返回 JSON
+func Negotiate(accept string) Codec {
+	if accept == "" {
+		return Default()
+	}
+	type weighted struct {
+		mediaType string
+		q         float64
+	}
+	var ranges []weighted
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		q := 1.0
+		if v, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(v, 64); err != nil {
+				continue
+			}
+		}
+		if q > 0 {
+			ranges = append(ranges, weighted{mediaType: mediaType, q: q})
+		}
+	}
+	sort.SliceStable(ranges, func(i, j int) bool {
+		return ranges[i].q > ranges[j].q
+	})
+	for _, r := range ranges {
+		if r.mediaType == "*/*" || r.mediaType == "application/*" {
+			return Default()
+		}
+		if c, ok := ForContentType(r.mediaType); ok {
+			return c
+		}
+	}
+	return Default()
+}
@@ -0,0 +1,53 @@
+package codec
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+func init() {
+	Register(jsonCodec{}, "text/json")
+	Register(msgpackCodec{}, "application/x-msgpack", "application/vnd.msgpack")
+	Register(protobufCodec{}, "application/protobuf", "application/vnd.google.protobuf")
+	Register(cborCodec{})
+}
+
+type jsonCodec struct{}
+
+func (jsonCodec) Name() string                       { return JSON }
+func (jsonCodec) ContentType() string                { return "application/json; charset=utf-8" }
+func (jsonCodec) Marshal(v any) ([]byte, error)      { return json.Marshal(v) }
+func (jsonCodec) Unmarshal(data []byte, v any) error { return json.Unmarshal(data, v) }
+
+// msgpackCodec 字段名沿用 json 标签，与 JSON 响应结构一致
+type msgpackCodec struct{}
+
+func (msgpackCodec) Name() string        { return MsgPack }
+func (msgpackCodec) ContentType() string { return "application/msgpack" }
+
+func (msgpackCodec) Marshal(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := msgpack.NewEncoder(&buf)
+	enc.SetCustomStructTag("json")
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (msgpackCodec) Unmarshal(data []byte, v any) error {
+	dec := msgpack.NewDecoder(bytes.NewReader(data))
+	dec.SetCustomStructTag("json")
+	return dec.Decode(v)
+}
+
+// cborCodec 未声明 cbor 标签的字段沿用 json 标签
+type cborCodec struct{}
+
+func (cborCodec) Name() string                       { return CBOR }
+func (cborCodec) ContentType() string                { return "application/cbor" }
+func (cborCodec) Marshal(v any) ([]byte, error)      { return cbor.Marshal(v) }
+func (cborCodec) Unmarshal(data []byte, v any) error { return cbor.Unmarshal(data, v) }
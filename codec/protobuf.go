@@ -0,0 +1,122 @@
+package codec
+
+import (
+	"encoding/json"
+	"errors"
+	"go-wire/constant"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+var errNotProto = errors.New("protobuf 仅支持 proto.Message")
+
+// envelope 统一响应信封的 protobuf 描述，客户端按以下定义解码:
+//
+//	syntax = "proto3";
+//	package gowire;
+//	import "google/protobuf/any.proto";
+//	message Envelope {
+//	  int32 code = 1;
+//	  string msg = 2;
+//	  google.protobuf.Any data = 3; // proto.Message 原样打包，其余数据打包为 google.protobuf.Value
+//	}
+var envelope = func() protoreflect.MessageDescriptor {
+	field := func(name string, number int32, typ descriptorpb.FieldDescriptorProto_Type, typeName string) *descriptorpb.FieldDescriptorProto {
+		f := &descriptorpb.FieldDescriptorProto{
+			Name:     proto.String(name),
+			JsonName: proto.String(name),
+			Number:   proto.Int32(number),
+			Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+			Type:     typ.Enum(),
+		}
+		if typeName != "" {
+			f.TypeName = proto.String(typeName)
+		}
+		return f
+	}
+	file, err := protodesc.NewFile(&descriptorpb.FileDescriptorProto{
+		Name:       proto.String("gowire/envelope.proto"),
+		Package:    proto.String("gowire"),
+		Syntax:     proto.String("proto3"),
+		Dependency: []string{"google/protobuf/any.proto"},
+		MessageType: []*descriptorpb.DescriptorProto{{
+			Name: proto.String("Envelope"),
+			Field: []*descriptorpb.FieldDescriptorProto{
+				field("code", 1, descriptorpb.FieldDescriptorProto_TYPE_INT32, ""),
+				field("msg", 2, descriptorpb.FieldDescriptorProto_TYPE_STRING, ""),
+				field("data", 3, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, ".google.protobuf.Any"),
+			},
+		}},
+	}, protoregistry.GlobalFiles)
+	if err != nil {
+		panic(err)
+	}
+	return file.Messages().ByName("Envelope")
+}()
+
+type protobufCodec struct{}
+
+func (protobufCodec) Name() string        { return Protobuf }
+func (protobufCodec) ContentType() string { return "application/x-protobuf" }
+
+func (protobufCodec) Marshal(v any) ([]byte, error) {
+	switch m := v.(type) {
+	case proto.Message:
+		return proto.Marshal(m)
+	case constant.Response:
+		return marshalEnvelope(m)
+	case *constant.Response:
+		return marshalEnvelope(*m)
+	}
+	return nil, errNotProto
+}
+
+func (protobufCodec) Unmarshal(data []byte, v any) error {
+	m, ok := v.(proto.Message)
+	if !ok {
+		return errNotProto
+	}
+	return proto.Unmarshal(data, m)
+}
+
+func marshalEnvelope(r constant.Response) ([]byte, error) {
+	msg := dynamicpb.NewMessage(envelope)
+	fields := envelope.Fields()
+	msg.Set(fields.ByName("code"), protoreflect.ValueOfInt32(int32(r.Code)))
+	msg.Set(fields.ByName("msg"), protoreflect.ValueOfString(r.Msg))
+	if r.Data != nil {
+		data, err := pack(r.Data)
+		if err != nil {
+			return nil, err
+		}
+		msg.Set(fields.ByName("data"), protoreflect.ValueOfMessage(data.ProtoReflect()))
+	}
+	return proto.Marshal(msg)
+}
+
+// pack proto.Message 直接打包为 Any，其余数据经 JSON 转换为 google.protobuf.Value
+func pack(data any) (*anypb.Any, error) {
+	if m, ok := data.(proto.Message); ok {
+		return anypb.New(m)
+	}
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	var generic any
+	if err = json.Unmarshal(raw, &generic); err != nil {
+		return nil, err
+	}
+	value, err := structpb.NewValue(generic)
+	if err != nil {
+		return nil, err
+	}
+	return anypb.New(value)
+}
@@ -3,6 +3,7 @@ package config
 import (
 	"flag"
 	"fmt"
+	"time"
 
 	"github.com/google/wire"
 	"github.com/spf13/viper"
@@ -16,11 +17,27 @@ type Config struct {
 		Limit    float64
 		Burst    int
 		ClientID string
+		// ClientScopes 兼容 ClientID 的 API Key 具备的 scope，默认无
+		ClientScopes []string
+		// TrustedProxies 可信代理地址或网段，仅这些代理转发的 X-Forwarded-For 用于识别客户端 IP
+		// 为空时不信任任何代理，客户端 IP 取连接地址
+		TrustedProxies []string
 	}
-	AllowOrigins      []string
-	AllowedOriginsMap map[string]struct{}
-	Redis             map[string]RedisConfig
-	Log               struct {
+	Limiter     LimiterConfig
+	Concurrency ConcurrencyConfig
+	Auth        AuthConfig
+	Router      RouterConfig
+	Cors        CorsConfig
+	Security    SecurityConfig
+	Timeout     TimeoutConfig
+	Idempotency IdempotencyConfig
+	Cache       CacheConfig
+	Compress    CompressConfig
+	OpenAPI     OpenAPIConfig
+	I18n        I18nConfig
+	Validation  ValidationConfig
+	Redis       map[string]RedisConfig
+	Log         struct {
 		Driver     string
 		Director   string // 日志文件夹
 		Level      string // 日志级别
@@ -31,10 +48,271 @@ type Config struct {
 	}
 }
 
+// RedisConfig redis 实例配置，按 Mode 创建对应拓扑的客户端
 type RedisConfig struct {
-	Addr     string
-	Password string
-	DB       int
+	Mode             string   // 部署模式: single|sentinel|cluster|ring，默认 single
+	Addr             string   // single 模式地址
+	Addrs            []string // cluster 种子节点或 ring 分片地址
+	MasterName       string   // sentinel 主节点名称
+	SentinelAddrs    []string // sentinel 节点地址
+	SentinelPassword string   // sentinel 节点密码
+	Username         string
+	Password         string
+	DB               int  // cluster 模式仅支持 0
+	ReadOnly         bool // 只读命令路由到从节点，cluster 模式有效；sentinel 模式需同时开启 RouteByLatency 或 RouteRandomly
+	RouteByLatency   bool // 只读命令路由到延迟最低的节点，隐含 ReadOnly
+	RouteRandomly    bool // 只读命令随机路由到主从节点，隐含 ReadOnly
+}
+
+// RouterConfig 路由配置
+type RouterConfig struct {
+	PublicPaths []string // 跳过认证的路径，/prefix/* 表示前缀匹配
+}
+
+// I18nConfig 多语言配置，请求语言按查询参数、Accept-Language 的顺序协商
+type I18nConfig struct {
+	Default string // 无法协商时使用的语言，默认 zh
+	Param   string // 指定语言的查询参数名，如 lang
+}
+
+// ValidationConfig 参数校验配置
+type ValidationConfig struct {
+	Redis string // redis_unique 校验使用的 redis 实例名，默认 default
+}
+
+// OpenAPIConfig 接口文档配置
+type OpenAPIConfig struct {
+	Enabled     bool   // 是否提供 /openapi.json 与 /docs
+	Title       string // 文档标题，默认取 App.Name
+	Version     string // 接口版本
+	Description string
+}
+
+// TimeoutConfig 请求处理超时，超时后请求 context 被取消并返回 504
+type TimeoutConfig struct {
+	Default time.Duration  // 默认超时，<=0 不限制
+	Routes  []TimeoutRoute // 路由级超时
+}
+
+// TimeoutRoute 按路由模板与请求方法配置的超时，SSE 等流式接口应设为 <0 关闭超时
+type TimeoutRoute struct {
+	Method  string        // 请求方法，为空匹配全部
+	Path    string        // 路由模板，如 /api/test
+	Timeout time.Duration // 超时时间，<0 不限制
+}
+
+// IdempotencyConfig 幂等键配置，仅对配置的非安全方法路由生效
+type IdempotencyConfig struct {
+	Redis   string             // 保存请求指纹与响应的 redis 实例名，默认 default
+	Header  string             // 幂等键请求头，默认 Idempotency-Key
+	TTL     time.Duration      // 响应保存时间，默认 24h
+	LockTTL time.Duration      // 处理中标记的过期时间，应大于请求超时，默认 1m
+	Wait    time.Duration      // 并发的重复请求等待首个请求完成的时间，0 直接返回 409
+	Routes  []IdempotencyRoute // 启用幂等的路由
+}
+
+// IdempotencyRoute 按路由模板与请求方法启用幂等
+type IdempotencyRoute struct {
+	Method   string // 请求方法，为空匹配全部非安全方法
+	Path     string // 路由模板，如 /api/orders
+	Required bool   // 是否必须携带幂等键
+}
+
+// CacheConfig GET 响应缓存配置
+type CacheConfig struct {
+	Driver     string       // 缓存存储: memory|redis，默认 memory
+	Redis      string       // redis 实例名
+	MaxEntries int          // memory 存储的最大条目数，默认 1000
+	Headers    []string     // 参与缓存键的请求头，如 Accept、Accept-Language
+	Routes     []CacheRoute // 启用缓存的路由
+}
+
+// CacheRoute 路由级缓存策略
+type CacheRoute struct {
+	Path    string        // 路由模板，如 /api/test
+	TTL     time.Duration // 缓存时间
+	Tags    []string      // 失效标签，{name} 替换为同名路径参数或查询参数，如 test:{id}
+	Headers []string      // 参与缓存键的请求头，为空沿用全局配置
+	Shared  bool          // 不同请求主体共用缓存，默认按主体隔离
+}
+
+// SecurityConfig 请求体大小限制与安全响应头
+type SecurityConfig struct {
+	MaxBodySize int64            // 请求体上限(字节)，默认 4MB，<0 不限制
+	Routes      []BodyLimitRoute // 路由级请求体上限
+	Headers     SecurityHeaders
+}
+
+// BodyLimitRoute 按路由模板与请求方法配置的请求体上限
+type BodyLimitRoute struct {
+	Method      string // 请求方法，为空匹配全部
+	Path        string // 路由模板，如 /api/upload
+	MaxBodySize int64  // 请求体上限(字节)，<0 不限制
+}
+
+// SecurityHeaders 安全响应头，为空的项不输出
+type SecurityHeaders struct {
+	HSTS           time.Duration // Strict-Transport-Security 的 max-age
+	HSTSSubdomains bool          // HSTS 是否包含子域名
+	HSTSPreload    bool          // HSTS 是否加入预加载列表
+	NoSniff        bool          // 是否输出 X-Content-Type-Options: nosniff
+	FrameOptions   string        // X-Frame-Options: DENY|SAMEORIGIN
+	ReferrerPolicy string        // Referrer-Policy
+	CSP            string        // Content-Security-Policy 模板，{nonce} 替换为每个请求的随机值
+	CSPReportOnly  bool          // 以 Content-Security-Policy-Report-Only 输出
+}
+
+// CompressConfig 响应压缩与请求体解压配置
+type CompressConfig struct {
+	Enabled         bool
+	Encodings       []string // 支持的编码，按优先级排列: zstd|br|gzip
+	MinSize         int      // 小于该字节数的响应不压缩，默认 1024
+	ExcludeTypes    []string // 不压缩的 Content-Type 前缀，如已压缩的 image/
+	Decompress      bool     // 是否解压带 Content-Encoding 的请求体
+	MaxDecompressed int64    // 解压后请求体上限(字节)，默认 10MB
+}
+
+// CorsConfig 跨域策略
+type CorsConfig struct {
+	AllowOrigins     []string      // 允许的来源，支持 * 与子域名通配 https://*.example.com
+	AllowOriginRegex []string      // 正则匹配的来源，需完整匹配
+	AllowMethods     []string      // 允许的请求方法
+	AllowHeaders     []string      // 允许的请求头
+	ExposeHeaders    []string      // 暴露给前端的响应头
+	AllowCredentials bool          // 是否允许携带凭证
+	MaxAge           time.Duration // 预检结果缓存时间
+	Groups           []CorsGroup   // 路由组覆盖，按前缀最长匹配
+}
+
+// CorsGroup 路由组跨域策略覆盖，未配置的字段沿用全局策略
+type CorsGroup struct {
+	Prefix           string
+	AllowMethods     []string
+	AllowHeaders     []string
+	ExposeHeaders    []string
+	AllowCredentials *bool
+	MaxAge           time.Duration
+}
+
+// LimiterConfig 限流配置，默认策略取 App.Limit/App.Burst
+type LimiterConfig struct {
+	Driver      string         // 限流存储: local|redis
+	Redis       string         // redis 实例名
+	Fallback    string         // redis 不可用时的策略: local|open|closed
+	Key         string         // 限流维度: ip|client|user 或自定义 key 函数名
+	IdleTimeout time.Duration  // 空闲限流器回收时间
+	Routes      []LimiterRoute // 路由级限流策略
+}
+
+// LimiterRoute 按路由模板与请求方法配置的限流策略
+type LimiterRoute struct {
+	Method string  // 请求方法，为空匹配全部
+	Path   string  // 路由模板，如 /api/test
+	Key    string  // 限流维度，为空沿用全局配置
+	Limit  float64 // 每秒令牌数，<=0 不限流
+	Burst  int     // 令牌桶容量
+}
+
+// ConcurrencyConfig 并发限制配置
+type ConcurrencyConfig struct {
+	Max          int                // 全局最大并发数，<=0 不限制
+	Queue        int                // 等待队列长度
+	QueueTimeout time.Duration      // 排队超时时间
+	Routes       []ConcurrencyRoute // 路由级并发限制
+	Adaptive     AdaptiveConfig     // 自适应并发限制
+}
+
+// ConcurrencyRoute 按路由模板与请求方法配置的并发限制
+type ConcurrencyRoute struct {
+	Method string // 请求方法，为空匹配全部
+	Path   string // 路由模板，如 /api/test
+	Max    int    // 最大并发数
+	Queue  int    // 等待队列长度
+}
+
+// AdaptiveConfig AIMD 自适应并发，按观测延迟在 [MinLimit, Max] 之间调整全局并发上限
+type AdaptiveConfig struct {
+	Enabled       bool
+	MinLimit      int           // 并发下限
+	TargetLatency time.Duration // 目标延迟，超过则乘性减小
+	Backoff       float64       // 乘性减小系数 (0,1)
+}
+
+// AuthConfig 认证配置
+type AuthConfig struct {
+	Authenticators []string       // 默认认证链，按顺序尝试: apikey|jwt|anonymous
+	Groups         []AuthGroup    // 路由组认证链，按前缀最长匹配
+	APIKeys        []APIKeyConfig // 静态 API Key 列表
+	JWT            JWTConfig
+	HMAC           HMACConfig
+}
+
+// AuthGroup 路由组认证链
+type AuthGroup struct {
+	Prefix         string   // 路由前缀，如 /api
+	Authenticators []string // 认证器名称
+}
+
+// APIKeyConfig 静态 API Key
+type APIKeyConfig struct {
+	Name      string
+	Key       string
+	Roles     []string
+	Scopes    []string
+	ExpiresAt string // 过期时间(RFC3339)，为空永不过期
+}
+
+// JWTConfig Bearer JWT 认证配置
+type JWTConfig struct {
+	Algorithms []string         // 允许的签名算法: HS256|RS256|ES256|EdDSA，默认 HS256
+	Secret     string           // HS256 默认密钥(不带 kid)
+	Keys       []JWTKey         // 静态密钥，按 kid 选择
+	JWKSFile   string           // 本地 JWKS 文件路径
+	JWKSReload time.Duration    // JWKS 文件变更检查间隔
+	Issuer     string           // 签发者
+	Audience   string           // 受众
+	Leeway     time.Duration    // 时钟偏差容忍
+	Claims     JWTClaimsConfig  // 声明映射
+	Refresh    JWTRefreshConfig // 滑动续期
+}
+
+// JWTKey JWT 密钥，HS256 使用 Secret，其余算法使用 PEM 文件
+type JWTKey struct {
+	Kid        string
+	Alg        string
+	Secret     string
+	PublicKey  string // 公钥 PEM 文件路径
+	PrivateKey string // 私钥 PEM 文件路径，仅续期签发时需要
+}
+
+// JWTClaimsConfig 声明到 Principal 的映射，支持 a.b 形式的嵌套路径
+type JWTClaimsConfig struct {
+	UserID string // 默认 sub
+	Roles  string // 默认 roles
+	Scopes string // 默认 scope
+}
+
+// JWTRefreshConfig 滑动续期，剩余有效期低于阈值时通过 New-Token/New-Expires-At 响应头下发新 token
+type JWTRefreshConfig struct {
+	Enabled   bool
+	Threshold time.Duration // 续期阈值，需大于 0 且小于 TTL
+	TTL       time.Duration // 新 token 有效期，需大于 0
+	Kid       string        // 签发使用的密钥，为空使用 Secret
+}
+
+// HMACConfig 服务间 HMAC 请求签名认证
+type HMACConfig struct {
+	Clients []HMACClient
+	Skew    time.Duration // 允许的时间戳偏差，默认 5m
+	Redis   string        // nonce 防重放存储的 redis 实例
+}
+
+// HMACClient 签名调用方
+type HMACClient struct {
+	ID     string
+	Secret string
+	Roles  []string
+	Scopes []string
 }
 
 var ProviderSet = wire.NewSet(NewConfig)
@@ -64,11 +342,6 @@ func NewConfig() (*Config, error) {
 		cfg.App.Port = *port
 	}
 
-	cfg.AllowedOriginsMap = make(map[string]struct{}, len(cfg.AllowOrigins))
-	for _, origin := range cfg.AllowOrigins {
-		cfg.AllowedOriginsMap[origin] = struct{}{}
-	}
-
 	fmt.Println("配置加载成功", cfg)
 	return &cfg, nil
 }
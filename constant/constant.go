@@ -1,8 +1,20 @@
 package constant
 
 const (
-	SUCCESS   int = 0  // 成功码
-	ERROR     int = -1 // 失败码
-	VALID     int = -2 // 校验码
-	FORBIDDEN int = -3 // 权限码
+	SUCCESS      int = 0   // 成功码
+	ERROR        int = -1  // 失败码
+	VALID        int = -2  // 校验码
+	FORBIDDEN    int = -3  // 权限码
+	UNAUTHORIZED int = -4  // 认证码
+	BAD_REQUEST  int = -5  // 请求格式错误码
+	NOT_FOUND    int = -6  // 资源不存在码
+	CONFLICT     int = -7  // 资源冲突码
+	TOO_MANY     int = -8  // 限流码
+	UNAVAILABLE  int = -9  // 服务不可用码
+	UNSUPPORTED  int = -10 // 不支持的媒体类型码
+	TOO_LARGE    int = -11 // 请求体过大码
+	TIMEOUT      int = -12 // 处理超时码
+	IDEM_MISSING int = -13 // 缺少幂等键码
+	IDEM_BUSY    int = -14 // 幂等请求处理中码
+	IDEM_REUSED  int = -15 // 幂等键重复使用码
 )
@@ -8,10 +8,6 @@ type Response struct {
 	Data any    `json:"data"`
 }
 
-type ErrorResponse struct {
-	Code int    `json:"code"`
-	Msg  string `json:"msg"`
-}
 type LogLayout struct {
 	Time      time.Time `json:"time"`                // 请求的时间
 	Status    int       `json:"status,omitempty"`    // HTTP响应状态码
@@ -23,4 +19,5 @@ type LogLayout struct {
 	Error     string    `json:"error,omitempty"`     // 错误信息
 	Cost      float64   `json:"cost,omitempty"`      // 请求耗时
 	Source    string    `json:"source,omitempty"`    // 请求来源
+	Codec     string    `json:"codec,omitempty"`     // 响应编码格式
 }
@@ -2,12 +2,16 @@ package controller
 
 import (
 	"encoding/json"
+	"go-wire/auth"
 	"go-wire/controller/dto"
+	"go-wire/errs"
 	"go-wire/logger"
+	"go-wire/paging"
 	"go-wire/service"
+	"go-wire/validation"
+	"net/http"
 
 	"github.com/gin-gonic/gin"
-	ut "github.com/go-playground/universal-translator"
 )
 
 type ApiController struct {
@@ -15,35 +19,45 @@ type ApiController struct {
 	service *service.ApiService
 }
 
-func NewApiController(service *service.ApiService, log logger.Logger, trans ut.Translator) *ApiController {
+func NewApiController(service *service.ApiService, log logger.Logger, validator *validation.Validator) *ApiController {
 	return &ApiController{
 		Controller: Controller{
-			log:   log,
-			trans: trans,
+			log:       log,
+			validator: validator,
 		},
 		service: service,
 	}
 }
 
-func (c *ApiController) RegisterRoutes(group *gin.RouterGroup) {
-	userGroup := group.Group("/api")
-	userGroup.GET("test", c.Test)
+func (c *ApiController) RegisterRoutes(routes *auth.Routes) {
+	userGroup := routes.Group("/api")
+	Register(userGroup.With(auth.RequireScopes("api:read")), http.MethodGet, "test", Handle(&c.Controller, c.Test))
+	Register(userGroup.With(auth.RequireScopes("api:read")), http.MethodGet, "tests", Handle(&c.Controller, c.List))
 }
 
-func (c *ApiController) Test(ctx *gin.Context) {
-	var testReq dto.TestRequest
-	if err := c.Valid(ctx, &testReq); err != nil {
-		return
-	}
-	c.InfoLog(ctx, "req", logger.KeyValue("req", testReq))
-	value, err := c.service.Test(ctx, testReq.Id)
+func (c *ApiController) Test(ctx *gin.Context, req *dto.TestRequest) (*dto.TestResponse, error) {
+	c.InfoLog(ctx, "req", logger.KeyValue("req", req))
+	value, err := c.service.Test(ctx.Request.Context(), req.Id)
 	if err != nil {
-		c.Error(ctx, "获取用户失败", err)
+		return nil, err
 	}
 	var testResp dto.TestResponse
-	err = json.Unmarshal([]byte(value), &testResp)
+	if err = json.Unmarshal([]byte(value), &testResp); err != nil {
+		return nil, errs.Internal.WithMsg("testResp 解析失败").Wrap(err)
+	}
+	return &testResp, nil
+}
+
+func (c *ApiController) List(ctx *gin.Context, req *dto.TestListRequest) (*paging.Page[dto.TestResponse], error) {
+	page, err := c.service.List(ctx.Request.Context(), req.Query)
 	if err != nil {
-		c.Error(ctx, "testResp 解析失败", err)
+		return nil, err
 	}
-	c.Success(ctx, testResp)
+	return paging.Map(page, func(value string) (dto.TestResponse, error) {
+		var item dto.TestResponse
+		if err := json.Unmarshal([]byte(value), &item); err != nil {
+			return item, errs.Internal.WithMsg("testResp 解析失败").Wrap(err)
+		}
+		return item, nil
+	})
 }
@@ -1,49 +1,53 @@
 package controller
 
 import (
-	"context"
 	"errors"
-	"go-wire/constant"
+	"go-wire/auth"
+	"go-wire/errs"
 	"go-wire/logger"
+	"go-wire/reqctx"
+	"go-wire/response"
+	"go-wire/validation"
 	"net/http"
 	"reflect"
-	"strings"
 
 	"github.com/gin-gonic/gin"
-	"github.com/gin-gonic/gin/binding"
-	"github.com/go-playground/locales/zh"
 	ut "github.com/go-playground/universal-translator"
 	"github.com/go-playground/validator/v10"
-	znTranslations "github.com/go-playground/validator/v10/translations/zh"
 )
 
+// RouteRegistrar 在带授权策略的路由组下注册路由
 type RouteRegistrar interface {
-	RegisterRoutes(group *gin.RouterGroup)
+	RegisterRoutes(routes *auth.Routes)
 }
 
 type Controller struct {
-	trans ut.Translator
-	log   logger.Logger
+	validator *validation.Validator
+	log       logger.Logger
 }
 
 func (c *Controller) Result(ctx *gin.Context, code int, msg string, data any) {
-	ctx.JSON(http.StatusOK, constant.Response{
-		Code: code,
-		Msg:  msg,
-		Data: data,
-	})
+	response.Result(ctx, http.StatusOK, code, msg, data)
 }
 
 func (c *Controller) Success(ctx *gin.Context, data any) {
-	c.Result(ctx, constant.SUCCESS, "请求成功", data)
+	response.Success(ctx, data)
 }
 
+// Error 记录日志并输出错误响应，业务错误按其响应码输出，其余错误以 msg 作为内部错误提示
 func (c *Controller) Error(ctx *gin.Context, msg string, err error) {
 	c.log.Error(ctx, msg, logger.Error(err))
-	panic(constant.ErrorResponse{
-		Code: constant.ERROR,
-		Msg:  msg,
-	})
+	var e *errs.Error
+	if !errors.As(err, &e) {
+		e = errs.Internal.WithMsg(msg).Wrap(err)
+	}
+	response.Error(ctx, e)
+}
+
+// Principal 当前请求主体，未认证时返回 nil
+func (c *Controller) Principal(ctx *gin.Context) *auth.Principal {
+	p, _ := reqctx.PrincipalFrom(ctx.Request.Context())
+	return p
 }
 
 func (c *Controller) InfoLog(ctx *gin.Context, msg string, filed ...logger.Field) {
@@ -53,62 +57,62 @@ func (c *Controller) InfoLog(ctx *gin.Context, msg string, filed ...logger.Field
 func (c *Controller) ErrorLog(ctx *gin.Context, msg string, filed ...logger.Field) {
 	c.log.Error(ctx, msg, filed...)
 }
-func NewTrans(log logger.Logger) (ut.Translator, error) {
-	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
-		v.RegisterTagNameFunc(func(field reflect.StructField) string {
-			name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
-			if name == "-" {
-				return ""
-			}
-			return name
-		})
-
-		zhT := zh.New()
-		uni := ut.New(zhT, zhT)
 
-		Trans, o := uni.GetTranslator("zh")
-		if !o {
-			log.Error(context.TODO(), "翻译器获取失败")
-			return nil, errors.New("翻译器获取失败")
-		}
-
-		if err := znTranslations.RegisterDefaultTranslations(v, Trans); err != nil {
-			log.Error(context.TODO(), "翻译器注册失败", logger.Error(err))
-			return nil, err
-		}
-		return Trans, nil
-	}
-	return nil, errors.New("翻译器注册失败")
+// translator 按请求语言选取校验提示翻译器
+func (c *Controller) translator(ctx *gin.Context) ut.Translator {
+	return c.validator.Translator(reqctx.Locale(ctx.Request.Context()))
 }
 
-// Valid 参数校验
+// Valid 参数校验，失败时直接输出错误响应
 func (c *Controller) Valid(ctx *gin.Context, valid interface{}) error {
-	if err := ctx.ShouldBind(valid); err != nil {
-		var errs validator.ValidationErrors
-		if errors.As(err, &errs) {
-			c.ErrorLog(ctx, "参数检验失败",
-				logger.StringAny("url", ctx.Request.URL.Path),
-				logger.StringAny("validationErrors", errs.Translate(c.trans)),
-			)
-			c.Result(ctx, constant.VALID, "请求参数校验失败", c.removeTopStruct(errs.Translate(c.trans)))
-		} else {
-			c.ErrorLog(ctx, "请求解析失败",
-				logger.StringAny("url", ctx.Request.URL.Path),
-				logger.Error(err),
-			)
-			c.Result(ctx, http.StatusBadRequest, err.Error(), nil)
-		}
+	if err := c.Bind(ctx, valid); err != nil {
+		response.Error(ctx, err)
 		return err
 	}
 	return nil
 }
 
-func (c *Controller) removeTopStruct(fields map[string]string) map[string]string {
-	res := make(map[string]string, len(fields))
-	for field, err := range fields {
-		if idx := strings.Index(field, "."); idx != -1 {
-			res[field[idx+1:]] = err
+// Bind 从路径、查询参数、请求头与请求体绑定参数并校验，失败时返回业务错误
+// 错误 data 为 []errs.FieldError: 校验失败返回 422，请求解析失败返回 400
+func (c *Controller) Bind(ctx *gin.Context, valid interface{}) error {
+	err := bindRequest(ctx, valid)
+	if err == nil {
+		err = c.validator.Struct(ctx.Request.Context(), valid)
+	}
+	if err == nil {
+		return nil
+	}
+
+	var verrs validator.ValidationErrors
+	if errors.As(err, &verrs) {
+		trans := c.translator(ctx)
+		fields := make([]errs.FieldError, 0, len(verrs))
+		for _, fe := range verrs {
+			fields = append(fields, errs.FieldError{
+				Field:   fieldPointer(reflect.TypeOf(valid), fe.StructNamespace()),
+				Rule:    fe.Tag(),
+				Param:   fe.Param(),
+				Message: fe.Translate(trans),
+			})
 		}
+		c.ErrorLog(ctx, "参数检验失败",
+			logger.StringAny("url", ctx.Request.URL.Path),
+			logger.StringAny("validationErrors", fields),
+		)
+		return errs.Invalid.WithDetails(fields).Wrap(err)
+	}
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		return errs.PayloadTooLarge.Wrap(err)
+	}
+	var e *errs.Error
+	if errors.As(err, &e) {
+		return err
 	}
-	return res
+	c.ErrorLog(ctx, "请求解析失败",
+		logger.StringAny("url", ctx.Request.URL.Path),
+		logger.Error(err),
+	)
+	field := parseError(reqctx.Locale(ctx.Request.Context()), err)
+	return errs.BadRequest.WithDetails([]errs.FieldError{field}).Wrap(err)
 }
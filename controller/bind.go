@@ -0,0 +1,164 @@
+package controller
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"go-wire/codec"
+	"go-wire/errs"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+const defaultMultipartMemory = 32 << 20
+
+// bindRequest 按 query -> body -> header -> path 的顺序绑定请求参数，后者覆盖前者
+// 字段标签: query/表单 form，请求头 header，路径参数 uri，请求体按 Content-Type 使用 json/xml/form
+// 请求头与路径参数仅绑定显式声明 header/uri 标签的字段，避免同名请求头覆盖查询参数或请求体
+// 仅绑定不校验，由调用方在全部来源绑定完成后统一校验，避免必填字段因来源不同而误报
+func bindRequest(ctx *gin.Context, obj any) error {
+	if err := mapForm(obj, ctx.Request.URL.Query(), "form"); err != nil {
+		return err
+	}
+	if err := bindBody(ctx, obj); err != nil {
+		return err
+	}
+	if err := mapTagged(obj, "header", ctx.Request.Header.Values); err != nil {
+		return err
+	}
+	return mapTagged(obj, "uri", paramValues(ctx.Params))
+}
+
+// mappingError 参数值无法转换为字段类型，Key 为出错的参数名
+type mappingError struct {
+	Key string
+	err error
+}
+
+func (e *mappingError) Error() string {
+	return e.Key + ": " + e.err.Error()
+}
+
+func (e *mappingError) Unwrap() error {
+	return e.err
+}
+
+// mapForm 按标签映射参数，失败时逐个参数重试以定位出错的参数名
+func mapForm(obj any, form map[string][]string, tag string) error {
+	err := binding.MapFormWithTag(obj, form, tag)
+	if err == nil {
+		return nil
+	}
+	typ := reflect.TypeOf(obj).Elem()
+	for key, values := range form {
+		if binding.MapFormWithTag(reflect.New(typ).Interface(), map[string][]string{key: values}, tag) != nil {
+			return &mappingError{Key: key, err: err}
+		}
+	}
+	return err
+}
+
+// bindBody 按 Content-Type 解码请求体，空请求体跳过，无法识别的 Content-Type 返回 415
+func bindBody(ctx *gin.Context, obj any) error {
+	req := ctx.Request
+	if req.Body == nil || req.Body == http.NoBody || req.ContentLength == 0 {
+		return nil
+	}
+	switch ctx.ContentType() {
+	case binding.MIMEJSON, "":
+		codec.Record("request", codec.JSON)
+		return ignoreEOF(decodeJSON(req.Body, obj))
+	case binding.MIMEXML, binding.MIMEXML2:
+		return ignoreEOF(xml.NewDecoder(req.Body).Decode(obj))
+	case binding.MIMEPOSTForm:
+		if err := req.ParseForm(); err != nil {
+			return err
+		}
+		return mapForm(obj, req.PostForm, "form")
+	case binding.MIMEMultipartPOSTForm:
+		if err := req.ParseMultipartForm(defaultMultipartMemory); err != nil {
+			return err
+		}
+		// 文件字段请在处理函数中通过 ctx.FormFile 读取
+		return mapForm(obj, req.MultipartForm.Value, "form")
+	}
+	// msgpack、protobuf、cbor 等已注册的编解码格式
+	if c, ok := codec.ForContentType(ctx.ContentType()); ok {
+		data, err := io.ReadAll(req.Body)
+		if err != nil {
+			return err
+		}
+		codec.Record("request", c.Name())
+		return c.Unmarshal(data, obj)
+	}
+	return errs.Unsupported
+}
+
+// decodeJSON 与 gin 的 JSON 绑定保持一致的解码选项
+func decodeJSON(r io.Reader, obj any) error {
+	decoder := json.NewDecoder(r)
+	if binding.EnableDecoderUseNumber {
+		decoder.UseNumber()
+	}
+	if binding.EnableDecoderDisallowUnknownFields {
+		decoder.DisallowUnknownFields()
+	}
+	return decoder.Decode(obj)
+}
+
+func ignoreEOF(err error) error {
+	if errors.Is(err, io.EOF) {
+		return nil
+	}
+	return err
+}
+
+// mapTagged 仅绑定显式声明 tag 标签的字段，gin 的 MapFormWithTag 对未声明标签的字段会回退到字段名匹配
+// 每个字段构造只含该字段的临时结构体交给 gin 转换，保持与 form 绑定一致的类型转换与 time_format 等选项
+func mapTagged(obj any, tag string, lookup func(key string) []string) error {
+	return mapTaggedValue(reflect.ValueOf(obj).Elem(), tag, lookup)
+}
+
+func mapTaggedValue(v reflect.Value, tag string, lookup func(key string) []string) error {
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			if err := mapTaggedValue(v.Field(i), tag, lookup); err != nil {
+				return err
+			}
+			continue
+		}
+		key, _, _ := strings.Cut(field.Tag.Get(tag), ",")
+		if key == "" || key == "-" || !field.IsExported() {
+			continue
+		}
+		values := lookup(key)
+		if len(values) == 0 {
+			continue
+		}
+		tmp := reflect.New(reflect.StructOf([]reflect.StructField{{Name: "V", Type: field.Type, Tag: field.Tag}}))
+		if err := binding.MapFormWithTag(tmp.Interface(), map[string][]string{key: values}, tag); err != nil {
+			return &mappingError{Key: key, err: err}
+		}
+		v.Field(i).Set(tmp.Elem().Field(0))
+	}
+	return nil
+}
+
+func paramValues(params gin.Params) func(key string) []string {
+	return func(key string) []string {
+		if value, ok := params.Get(key); ok {
+			return []string{value}
+		}
+		return nil
+	}
+}
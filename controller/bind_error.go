@@ -0,0 +1,144 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go-wire/errs"
+	"go-wire/i18n"
+	"io"
+	"reflect"
+	"strings"
+)
+
+// fieldPointer 将校验错误的结构体命名空间转换为 JSON Pointer
+// 如 CreateRequest.Items[0].Name -> /items/0/name，map 键 Tags[k] -> /tags/k
+// 按 json 标签取字段名，未声明 json 名称的匿名嵌入结构体不产生路径片段
+func fieldPointer(root reflect.Type, structNs string) string {
+	_, structNs, _ = strings.Cut(structNs, ".")
+	t := root
+	var path []string
+	for _, seg := range splitNamespace(structNs) {
+		for t != nil && t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+		if seg.index {
+			path = append(path, seg.name)
+			if t != nil && (t.Kind() == reflect.Slice || t.Kind() == reflect.Array || t.Kind() == reflect.Map) {
+				t = t.Elem()
+			}
+			continue
+		}
+		if t == nil || t.Kind() != reflect.Struct {
+			path = append(path, seg.name)
+			t = nil
+			continue
+		}
+		sf, ok := t.FieldByName(seg.name)
+		if !ok {
+			path = append(path, seg.name)
+			t = nil
+			continue
+		}
+		t = sf.Type
+		name, _, _ := strings.Cut(sf.Tag.Get("json"), ",")
+		if sf.Anonymous && name == "" {
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+		path = append(path, name)
+	}
+	return errs.Pointer(path...)
+}
+
+type nsSegment struct {
+	name  string
+	index bool // 下标或 map 键
+}
+
+// splitNamespace 拆分命名空间，如 Items[0].Name -> Items, [0], Name
+func splitNamespace(ns string) []nsSegment {
+	var (
+		segments []nsSegment
+		cur      strings.Builder
+	)
+	flush := func() {
+		if cur.Len() > 0 {
+			segments = append(segments, nsSegment{name: cur.String()})
+			cur.Reset()
+		}
+	}
+	for i := 0; i < len(ns); i++ {
+		switch ns[i] {
+		case '.':
+			flush()
+		case '[':
+			flush()
+			end := strings.IndexByte(ns[i:], ']')
+			if end == -1 {
+				cur.WriteString(ns[i+1:])
+				i = len(ns)
+				continue
+			}
+			segments = append(segments, nsSegment{name: ns[i+1 : i+end], index: true})
+			i += end
+		default:
+			cur.WriteByte(ns[i])
+		}
+	}
+	flush()
+	return segments
+}
+
+// parseError 将请求解析错误转换为字段错误，JSON 语法与类型错误携带偏移量
+func parseError(locale string, err error) errs.FieldError {
+	var (
+		syntaxErr *json.SyntaxError
+		typeErr   *json.UnmarshalTypeError
+		mapErr    *mappingError
+	)
+	switch {
+	case errors.As(err, &syntaxErr):
+		return errs.FieldError{
+			Rule:    "syntax",
+			Message: i18n.Message(locale, "invalid_json", "请求体不是合法的 JSON"),
+			Offset:  &syntaxErr.Offset,
+		}
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		return errs.FieldError{
+			Rule:    "syntax",
+			Message: i18n.Message(locale, "invalid_json", "请求体不是合法的 JSON"),
+		}
+	case errors.As(err, &typeErr):
+		field := errs.Pointer(strings.Split(typeErr.Field, ".")...)
+		if typeErr.Field == "" {
+			field = ""
+		}
+		return errs.FieldError{
+			Field:   field,
+			Rule:    "type",
+			Param:   typeErr.Type.String(),
+			Message: fmt.Sprintf(i18n.Message(locale, "invalid_type", "%s 类型错误，应为 %s"), typeErr.Field, typeErr.Type),
+			Offset:  &typeErr.Offset,
+		}
+	case strings.HasPrefix(err.Error(), `json: unknown field "`):
+		name := strings.TrimSuffix(strings.TrimPrefix(err.Error(), `json: unknown field "`), `"`)
+		return errs.FieldError{
+			Field:   errs.Pointer(name),
+			Rule:    "unknown",
+			Message: fmt.Sprintf(i18n.Message(locale, "unknown_field", "不支持的字段 %s"), name),
+		}
+	case errors.As(err, &mapErr):
+		return errs.FieldError{
+			Field:   errs.Pointer(mapErr.Key),
+			Rule:    "type",
+			Message: fmt.Sprintf(i18n.Message(locale, "invalid_value", "参数 %s 格式错误"), mapErr.Key),
+		}
+	}
+	return errs.FieldError{
+		Rule:    "malformed",
+		Message: i18n.Message(locale, errs.BadRequest.Key, errs.BadRequest.Msg),
+	}
+}
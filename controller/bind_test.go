@@ -0,0 +1,66 @@
+package controller
+
+import (
+	"errors"
+	"go-wire/errs"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+type bindTestRequest struct {
+	Page  int    `form:"page" json:"page"`
+	Name  string `json:"name"`
+	Token string `header:"X-Token"`
+	ID    string `uri:"id"`
+}
+
+func bindTest(t *testing.T, req *http.Request) (bindTestRequest, error) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	var (
+		got bindTestRequest
+		err error
+	)
+	engine := gin.New()
+	engine.Any("/items/:id", func(ctx *gin.Context) {
+		err = bindRequest(ctx, &got)
+	})
+	engine.ServeHTTP(httptest.NewRecorder(), req)
+	return got, err
+}
+
+func TestBindRequestSources(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/items/42?page=2", strings.NewReader(`{"name":"body"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Token", "t1")
+	// 未声明 header 标签的字段不绑定同名请求头
+	req.Header.Set("Page", "9")
+	req.Header.Set("Name", "header")
+	req.Header.Set("Id", "header")
+
+	got, err := bindTest(t, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := bindTestRequest{Page: 2, Name: "body", Token: "t1", ID: "42"}
+	if got != want {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestBindRequestUnsupportedContentType(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/items/42", strings.NewReader("name=body"))
+	req.Header.Set("Content-Type", "text/plain")
+
+	_, err := bindTest(t, req)
+	if !errors.Is(err, errs.Unsupported) {
+		t.Fatalf("err=%v, want %v", err, errs.Unsupported)
+	}
+	if e := errs.From(err); e.Status != http.StatusUnsupportedMediaType {
+		t.Fatalf("status=%d, want 415", e.Status)
+	}
+}
@@ -1,5 +1,12 @@
 package dto
 
+import "go-wire/paging"
+
 type TestRequest struct {
 	Id string `json:"id" form:"id" binding:"required"`
 }
+
+// TestListRequest 列表查询，sort 支持 createdAt
+type TestListRequest struct {
+	paging.Query
+}
@@ -0,0 +1,76 @@
+package controller
+
+import (
+	"go-wire/auth"
+	"go-wire/errs"
+	"go-wire/logger"
+	"go-wire/response"
+	"net/http"
+	"reflect"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HandlerFunc 返回 error 的处理函数，错误统一由响应渲染器输出
+type HandlerFunc func(ctx *gin.Context) error
+
+// Wrap 将 HandlerFunc 适配为 gin.HandlerFunc，内部错误记录日志后输出
+func (c *Controller) Wrap(fn HandlerFunc) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		c.fail(ctx, fn(ctx))
+	}
+}
+
+func (c *Controller) fail(ctx *gin.Context, err error) {
+	if err == nil {
+		return
+	}
+	if e := errs.From(err); e.Status >= http.StatusInternalServerError {
+		c.ErrorLog(ctx, e.Msg, logger.Error(err))
+	}
+	response.Error(ctx, err)
+}
+
+// Endpoint 类型化处理函数及其请求与响应类型，请求与响应类型供文档生成使用
+type Endpoint struct {
+	Handler  gin.HandlerFunc
+	Request  reflect.Type
+	Response reflect.Type
+}
+
+// Handle 类型化处理函数: 从路径、查询参数、请求头与请求体绑定 Req 并校验，调用 fn，成功时输出 Resp
+// 通过 Register 注册路由以记录请求与响应类型，直接挂载 Endpoint.Handler 时不生成类型文档
+func Handle[Req, Resp any](c *Controller, fn func(ctx *gin.Context, req *Req) (*Resp, error)) Endpoint {
+	return Endpoint{
+		Handler: func(ctx *gin.Context) {
+			var req Req
+			if err := c.Bind(ctx, &req); err != nil {
+				c.fail(ctx, err)
+				return
+			}
+			resp, err := fn(ctx, &req)
+			if err != nil {
+				c.fail(ctx, err)
+				return
+			}
+			c.Success(ctx, resp)
+		},
+		Request:  reflect.TypeFor[Req](),
+		Response: reflect.TypeFor[Resp](),
+	}
+}
+
+// Register 注册类型化处理函数，并在路由上记录请求与响应类型
+func Register(routes *auth.Routes, method, relativePath string, ep Endpoint) {
+	routes.Annotate(ep).Handle(method, relativePath, ep.Handler)
+}
+
+// Describe 返回通过 Register 注册的路由的请求与响应类型，其他路由返回 false
+func Describe(authz *auth.Authorizer, method, fullPath string) (Endpoint, bool) {
+	v, ok := authz.Annotation(method, fullPath)
+	if !ok {
+		return Endpoint{}, false
+	}
+	ep, ok := v.(Endpoint)
+	return ep, ok
+}
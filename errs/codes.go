@@ -0,0 +1,30 @@
+package errs
+
+import (
+	"go-wire/constant"
+	"net/http"
+)
+
+// 通用错误
+var (
+	Internal           = Register(constant.ERROR, http.StatusInternalServerError, "internal", "服务器开小差，请稍后再试")
+	Invalid            = Register(constant.VALID, http.StatusUnprocessableEntity, "invalid", "请求参数校验失败")
+	Forbidden          = Register(constant.FORBIDDEN, http.StatusForbidden, "forbidden", "无访问权限")
+	Unauthorized       = Register(constant.UNAUTHORIZED, http.StatusUnauthorized, "unauthorized", "无权限")
+	BadRequest         = Register(constant.BAD_REQUEST, http.StatusBadRequest, "bad_request", "请求解析失败")
+	NotFound           = Register(constant.NOT_FOUND, http.StatusNotFound, "not_found", "资源不存在")
+	Conflict           = Register(constant.CONFLICT, http.StatusConflict, "conflict", "资源冲突")
+	TooManyRequests    = Register(constant.TOO_MANY, http.StatusTooManyRequests, "too_many_requests", "服务繁忙，请稍后再试...")
+	Unavailable        = Register(constant.UNAVAILABLE, http.StatusServiceUnavailable, "unavailable", "服务繁忙，请稍后再试...")
+	Unsupported        = Register(constant.UNSUPPORTED, http.StatusUnsupportedMediaType, "unsupported_media_type", "不支持的请求体格式")
+	PayloadTooLarge    = Register(constant.TOO_LARGE, http.StatusRequestEntityTooLarge, "payload_too_large", "请求体过大")
+	Timeout            = Register(constant.TIMEOUT, http.StatusGatewayTimeout, "timeout", "请求处理超时，请稍后再试")
+	IdempotencyMissing = Register(constant.IDEM_MISSING, http.StatusBadRequest, "idempotency_missing", "缺少幂等键")
+	IdempotencyBusy    = Register(constant.IDEM_BUSY, http.StatusConflict, "idempotency_busy", "相同请求正在处理中，请稍后重试")
+	IdempotencyReused  = Register(constant.IDEM_REUSED, http.StatusUnprocessableEntity, "idempotency_reused", "幂等键已用于不同的请求")
+)
+
+// 业务错误，响应码从 10000 开始按模块分段
+var (
+	UserNotFound = Register(10001, http.StatusNotFound, "user_not_found", "用户不存在")
+)
@@ -0,0 +1,119 @@
+package errs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Error 业务错误，Code 为响应码，Status 为 HTTP 状态码，Key 为消息 key
+type Error struct {
+	Code    int
+	Status  int
+	Key     string
+	Msg     string
+	Details any
+	cause   error
+}
+
+func (e *Error) Error() string {
+	if e.cause != nil {
+		return fmt.Sprintf("%d %s: %v", e.Code, e.Msg, e.cause)
+	}
+	return fmt.Sprintf("%d %s", e.Code, e.Msg)
+}
+
+func (e *Error) Unwrap() error {
+	return e.cause
+}
+
+// Is 响应码相同即视为同一错误
+func (e *Error) Is(target error) bool {
+	var t *Error
+	return errors.As(target, &t) && t.Code == e.Code
+}
+
+// Wrap 返回携带原因的副本
+func (e *Error) Wrap(cause error) *Error {
+	c := *e
+	c.cause = cause
+	return &c
+}
+
+// WithMsg 返回替换提示信息的副本
+func (e *Error) WithMsg(msg string) *Error {
+	c := *e
+	c.Msg = msg
+	return &c
+}
+
+// WithDetails 返回携带详情的副本，详情作为响应 data 返回
+func (e *Error) WithDetails(details any) *Error {
+	c := *e
+	c.Details = details
+	return &c
+}
+
+var registry = struct {
+	sync.RWMutex
+	codes map[int]*Error
+}{codes: make(map[int]*Error)}
+
+// Register 注册错误码，重复注册会 panic，应在包初始化时调用
+func Register(code, status int, key, msg string) *Error {
+	registry.Lock()
+	defer registry.Unlock()
+	if _, ok := registry.codes[code]; ok {
+		panic(fmt.Sprintf("错误码 %d 重复注册", code))
+	}
+	e := &Error{Code: code, Status: status, Key: key, Msg: msg}
+	registry.codes[code] = e
+	return e
+}
+
+// Lookup 按响应码查找已注册的错误
+func Lookup(code int) (*Error, bool) {
+	registry.RLock()
+	defer registry.RUnlock()
+	e, ok := registry.codes[code]
+	return e, ok
+}
+
+// All 返回全部已注册错误，按响应码排序
+func All() []*Error {
+	registry.RLock()
+	defer registry.RUnlock()
+	res := make([]*Error, 0, len(registry.codes))
+	for _, e := range registry.codes {
+		res = append(res, e)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Code < res[j].Code
+	})
+	return res
+}
+
+// New 按响应码创建错误，未注册时视为内部错误
+func New(code int) *Error {
+	if e, ok := Lookup(code); ok {
+		return e.Wrap(nil)
+	}
+	return Internal.Wrap(fmt.Errorf("未注册的错误码 %d", code))
+}
+
+// From 将任意 error 转换为业务错误，请求超时转换为 Timeout，其余非业务错误包装为内部错误
+func From(err error) *Error {
+	if err == nil {
+		return nil
+	}
+	var e *Error
+	if errors.As(err, &e) {
+		return e
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return Timeout.Wrap(err)
+	}
+	return Internal.Wrap(err)
+}
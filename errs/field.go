@@ -0,0 +1,25 @@
+package errs
+
+import "strings"
+
+// FieldError 字段级错误，作为参数校验与请求解析错误的响应 data
+type FieldError struct {
+	Field   string `json:"field"`            // 字段 JSON Pointer，如 /items/0/name，整体错误为空
+	Rule    string `json:"rule"`             // 未通过的规则，如 required、type、syntax
+	Param   string `json:"param,omitempty"`  // 规则参数
+	Message string `json:"message"`          // 本地化提示
+	Offset  *int64 `json:"offset,omitempty"` // 请求体解析错误的字节偏移
+}
+
+// Pointer 将路径片段转换为 JSON Pointer (RFC 6901)
+func Pointer(segments ...string) string {
+	if len(segments) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	for _, s := range segments {
+		b.WriteByte('/')
+		b.WriteString(strings.NewReplacer("~", "~0", "/", "~1").Replace(s))
+	}
+	return b.String()
+}
@@ -0,0 +1,89 @@
+package i18n
+
+import "sync"
+
+// catalog 消息目录: 语言 -> 消息 key -> 文本
+var catalog = struct {
+	sync.RWMutex
+	messages map[string]map[string]string
+}{messages: make(map[string]map[string]string)}
+
+// Register 注册某语言的消息，应在包初始化时调用
+func Register(locale string, messages map[string]string) {
+	catalog.Lock()
+	defer catalog.Unlock()
+	m, ok := catalog.messages[locale]
+	if !ok {
+		m = make(map[string]string, len(messages))
+		catalog.messages[locale] = m
+	}
+	for k, v := range messages {
+		m[k] = v
+	}
+}
+
+// Message 按语言查找消息，依次回退到默认语言与 fallback
+func Message(locale, key, fallback string) string {
+	catalog.RLock()
+	defer catalog.RUnlock()
+	if msg, ok := catalog.messages[locale][key]; ok {
+		return msg
+	}
+	if msg, ok := catalog.messages[Default][key]; ok {
+		return msg
+	}
+	return fallback
+}
+
+func init() {
+	Register(ZH, map[string]string{
+		"success":                "请求成功",
+		"internal":               "服务器开小差，请稍后再试",
+		"invalid":                "请求参数校验失败",
+		"forbidden":              "无访问权限",
+		"unauthorized":           "无权限",
+		"bad_request":            "请求解析失败",
+		"not_found":              "资源不存在",
+		"conflict":               "资源冲突",
+		"too_many_requests":      "服务繁忙，请稍后再试...",
+		"unavailable":            "服务繁忙，请稍后再试...",
+		"payload_too_large":      "请求体过大",
+		"unsupported_media_type": "不支持的请求体格式",
+		"timeout":                "请求处理超时，请稍后再试",
+		"idempotency_missing":    "缺少幂等键",
+		"idempotency_busy":       "相同请求正在处理中，请稍后重试",
+		"idempotency_reused":     "幂等键已用于不同的请求",
+		"user_not_found":         "用户不存在",
+		"invalid_json":           "请求体不是合法的 JSON",
+		"invalid_type":           "%s 类型错误，应为 %s",
+		"unknown_field":          "不支持的字段 %s",
+		"invalid_value":          "参数 %s 格式错误",
+		"unsupported_sort":       "不支持按 %s 排序",
+		"invalid_cursor":         "游标 %s 无效",
+	})
+	Register(EN, map[string]string{
+		"success":                "Success",
+		"internal":               "Internal server error, please try again later",
+		"invalid":                "Request validation failed",
+		"forbidden":              "Access denied",
+		"unauthorized":           "Unauthorized",
+		"bad_request":            "Malformed request",
+		"not_found":              "Resource not found",
+		"conflict":               "Resource conflict",
+		"too_many_requests":      "Too many requests, please try again later",
+		"unavailable":            "Service busy, please try again later",
+		"payload_too_large":      "Request body too large",
+		"unsupported_media_type": "Unsupported request body format",
+		"timeout":                "Request timed out, please try again later",
+		"idempotency_missing":    "Missing idempotency key",
+		"idempotency_busy":       "An identical request is still being processed, please retry later",
+		"idempotency_reused":     "Idempotency key was already used for a different request",
+		"user_not_found":         "User not found",
+		"invalid_json":           "Request body is not valid JSON",
+		"invalid_type":           "%s has the wrong type, expected %s",
+		"unknown_field":          "Unknown field %s",
+		"invalid_value":          "Malformed parameter %s",
+		"unsupported_sort":       "Sorting by %s is not supported",
+		"invalid_cursor":         "Invalid cursor %s",
+	})
+}
@@ -0,0 +1,83 @@
+package i18n
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	ZH = "zh"
+	EN = "en"
+)
+
+// Default 未协商出受支持语言时使用的语言
+var Default = ZH
+
+// Supported 受支持的语言，需同时在消息目录与校验翻译器中注册
+var Supported = []string{ZH, EN}
+
+// Negotiate 按查询参数、Accept-Language 的顺序协商请求语言，均不受支持时返回 fallback
+func Negotiate(r *http.Request, param, fallback string) string {
+	if param != "" {
+		if locale, ok := Match(r.URL.Query().Get(param)); ok {
+			return locale
+		}
+	}
+	for _, tag := range parseAcceptLanguage(r.Header.Get("Accept-Language")) {
+		if locale, ok := Match(tag); ok {
+			return locale
+		}
+	}
+	return fallback
+}
+
+// Match 将语言标签匹配到受支持的语言，如 en-US -> en、zh_Hans_CN -> zh
+func Match(tag string) (string, bool) {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if tag == "" {
+		return "", false
+	}
+	base, _, _ := strings.Cut(strings.ReplaceAll(tag, "_", "-"), "-")
+	for _, locale := range Supported {
+		if tag == locale || base == locale {
+			return locale, true
+		}
+	}
+	return "", false
+}
+
+// parseAcceptLanguage 按权重从高到低返回语言标签，忽略 q=0 与通配符
+func parseAcceptLanguage(header string) []string {
+	type weighted struct {
+		tag string
+		q   float64
+	}
+	var tags []weighted
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if tag == "" || tag == "*" {
+			continue
+		}
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if q > 0 {
+			tags = append(tags, weighted{tag: tag, q: q})
+		}
+	}
+	sort.SliceStable(tags, func(i, j int) bool {
+		return tags[i].q > tags[j].q
+	})
+	res := make([]string, len(tags))
+	for i, t := range tags {
+		res[i] = t.tag
+	}
+	return res
+}
@@ -0,0 +1,17 @@
+package logger
+
+import "context"
+
+type nopLogger struct{}
+
+// NewNop 丢弃全部日志，用于命令行工具等无需输出日志的场景
+func NewNop() Logger {
+	return nopLogger{}
+}
+
+func (nopLogger) Debug(context.Context, string, ...Field) {}
+func (nopLogger) Info(context.Context, string, ...Field)  {}
+func (nopLogger) Warn(context.Context, string, ...Field)  {}
+func (nopLogger) Error(context.Context, string, ...Field) {}
+func (nopLogger) Fatal(context.Context, string, ...Field) {}
+func (nopLogger) Sync() error                             { return nil }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"go-wire/config"
+	"go-wire/reqctx"
 	"go-wire/util"
 	"os"
 	"path"
@@ -64,7 +65,7 @@ func (l *zapLogger) toZapFields(fields []Field) []zap.Field {
 
 // logWithTraceID 带有 TraceID 的日志记录
 func (l *zapLogger) withTrace(ctx context.Context, level zapcore.Level, msg string, fields ...Field) {
-	traceID, _ := ctx.Value("TraceID").(string)
+	traceID := reqctx.TraceID(ctx)
 	if l.log.Core().Enabled(level) {
 		l.log.With(zap.Any("trace_id", traceID)).WithOptions(zap.AddCallerSkip(2)).Log(level, msg, l.toZapFields(fields)...)
 	}
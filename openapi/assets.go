@@ -0,0 +1,21 @@
+package openapi
+
+import (
+	"crypto/sha512"
+	_ "embed"
+	"encoding/base64"
+)
+
+// RedocVersion 打包的 redoc 版本，升级时修改后重新执行 go generate
+const RedocVersion = "2.1.5"
+
+//go:generate curl -fsSL -o assets/redoc.standalone.js https://cdn.jsdelivr.net/npm/redoc@2.1.5/bundles/redoc.standalone.js
+
+//go:embed assets/redoc.standalone.js
+var redocJS []byte
+
+// redocIntegrity 页面 script 标签的 SRI 摘要，由打包内容计算
+var redocIntegrity = func() string {
+	sum := sha512.Sum384(redocJS)
+	return "sha384-" + base64.StdEncoding.EncodeToString(sum[:])
+}()
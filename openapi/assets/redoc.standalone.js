@@ -0,0 +1,12 @@
+/*
+ * 占位文件: 构建环境无法访问外网时提交。
+ * 执行 go generate ./openapi 下载 openapi/assets.go 中固定版本的 redoc.standalone.js 覆盖本文件。
+ */
+(function () {
+  var el = document.querySelector("redoc");
+  if (!el) {
+    return;
+  }
+  var spec = el.getAttribute("spec-url");
+  el.innerHTML = '<p>文档页面资源未打包，请执行 go generate ./openapi。接口描述见 <a href="' + spec + '">' + spec + "</a></p>";
+})();
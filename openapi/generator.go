@@ -0,0 +1,267 @@
+package openapi
+
+import (
+	"fmt"
+	"go-wire/auth"
+	"go-wire/controller"
+	"go-wire/errs"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/gin-gonic/gin"
+)
+
+const mimeJSON = "application/json"
+
+// Build 根据已注册路由生成文档
+// 通过 controller.Register 注册的路由附带请求与响应结构，其余路由仅生成路径参数与通用响应
+func Build(info Info, routes gin.RoutesInfo, authz *auth.Authorizer) *Document {
+	doc := &Document{
+		OpenAPI: Version,
+		Info:    info,
+		Paths:   make(map[string]*PathItem),
+		Components: Components{
+			Schemas:   make(map[string]*Schema),
+			Responses: make(map[string]*Response),
+		},
+	}
+	s := newSchemas(doc.Components.Schemas)
+	errStatuses := errorResponses(doc, s)
+
+	routes = append(gin.RoutesInfo(nil), routes...)
+	sort.Slice(routes, func(i, j int) bool {
+		if routes[i].Path != routes[j].Path {
+			return routes[i].Path < routes[j].Path
+		}
+		return routes[i].Method < routes[j].Method
+	})
+
+	ids := make(map[string]int)
+	for _, r := range routes {
+		p, params := convertPath(r.Path)
+		item, ok := doc.Paths[p]
+		if !ok {
+			item = &PathItem{}
+			doc.Paths[p] = item
+		}
+		op := &Operation{
+			OperationID: operationID(r.Method, r.Path, ids),
+			Tags:        tags(r.Path),
+			Responses:   make(map[string]*Response),
+		}
+		ep, typed := controller.Describe(authz, r.Method, r.Path)
+		if typed {
+			describeRequest(s, op, r.Method, params, ep.Request)
+		} else {
+			for _, name := range params {
+				op.Parameters = append(op.Parameters, &Parameter{Name: name, In: "path", Required: true, Schema: &Schema{Type: "string"}})
+			}
+		}
+		op.Responses[strconv.Itoa(http.StatusOK)] = success(s, ep.Response)
+		for _, status := range errStatuses {
+			op.Responses[status] = &Response{Ref: "#/components/responses/" + status}
+		}
+		(*item)[strings.ToLower(r.Method)] = op
+	}
+	return doc
+}
+
+// errorResponses 按 HTTP 状态码汇总已注册的错误码，返回状态码列表
+// 参数校验与请求解析错误的 data 为字段错误列表
+func errorResponses(doc *Document, s *schemas) []string {
+	doc.Components.Schemas["Response"] = &Schema{
+		Type:        "object",
+		Description: "统一响应结构",
+		Properties: map[string]*Schema{
+			"code": {Type: "integer", Description: "响应码，0 为成功"},
+			"msg":  {Type: "string", Description: "提示信息"},
+			"data": {Description: "响应数据"},
+		},
+		Required: []string{"code", "msg"},
+	}
+
+	codes := make(map[int][]*errs.Error)
+	var statuses []int
+	all := &Schema{Type: "integer", Description: "错误码"}
+	for _, e := range errs.All() {
+		if _, ok := codes[e.Status]; !ok {
+			statuses = append(statuses, e.Status)
+		}
+		codes[e.Status] = append(codes[e.Status], e)
+		all.Enum = append(all.Enum, e.Code)
+		all.Description += fmt.Sprintf("\n- `%d` %s: %s", e.Code, e.Key, e.Msg)
+	}
+	doc.Components.Schemas["ErrorCode"] = all
+
+	sort.Ints(statuses)
+	res := make([]string, 0, len(statuses))
+	for _, status := range statuses {
+		code := &Schema{Type: "integer"}
+		desc := make([]string, 0, len(codes[status]))
+		for _, e := range codes[status] {
+			code.Enum = append(code.Enum, e.Code)
+			desc = append(desc, fmt.Sprintf("%s(%d)", e.Msg, e.Code))
+		}
+		props := map[string]*Schema{"code": code}
+		if status == http.StatusBadRequest || status == http.StatusUnprocessableEntity {
+			props["data"] = s.of(reflect.TypeFor[[]errs.FieldError]())
+		}
+		key := strconv.Itoa(status)
+		doc.Components.Responses[key] = &Response{
+			Description: strings.Join(desc, "; "),
+			Content: map[string]*MediaType{mimeJSON: {Schema: &Schema{AllOf: []*Schema{
+				ref("Response"),
+				{Type: "object", Properties: props},
+			}}}},
+		}
+		res = append(res, key)
+	}
+	return res
+}
+
+// success 成功响应，响应结构作为信封的 data 字段
+func success(s *schemas, resp reflect.Type) *Response {
+	sc := ref("Response")
+	if resp != nil {
+		sc = &Schema{AllOf: []*Schema{
+			ref("Response"),
+			{Type: "object", Properties: map[string]*Schema{"data": s.of(resp)}},
+		}}
+	}
+	return &Response{
+		Description: "请求成功",
+		Content:     map[string]*MediaType{mimeJSON: {Schema: sc}},
+	}
+}
+
+// describeRequest 按字段标签生成参数与请求体: uri 为路径参数，header 为请求头，
+// form 在无请求体的方法中为查询参数，其余 json 字段组成请求体
+func describeRequest(s *schemas, op *Operation, method string, pathParams []string, req reflect.Type) {
+	typedPath := make(map[string]struct{})
+	bound := false
+	for _, f := range fieldsOf(req, "uri") {
+		op.Parameters = append(op.Parameters, parameter(s, f, "path"))
+		typedPath[f.Name] = struct{}{}
+		bound = true
+	}
+	for _, name := range pathParams {
+		if _, ok := typedPath[name]; !ok {
+			op.Parameters = append(op.Parameters, &Parameter{Name: name, In: "path", Required: true, Schema: &Schema{Type: "string"}})
+		}
+	}
+	for _, f := range fieldsOf(req, "header") {
+		op.Parameters = append(op.Parameters, parameter(s, f, "header"))
+		bound = true
+	}
+
+	hasBody := method != http.MethodGet && method != http.MethodHead && method != http.MethodDelete
+	for _, f := range fieldsOf(req, "form") {
+		if _, ok := f.Tag.Lookup("json"); hasBody && ok {
+			continue
+		}
+		op.Parameters = append(op.Parameters, parameter(s, f, "query"))
+		bound = true
+	}
+	if !hasBody {
+		return
+	}
+
+	// 请求体仅包含带 json 标签且未绑定到其他位置的字段，全部字段均属于请求体时直接引用 DTO
+	body := s.of(req)
+	if bound {
+		body = &Schema{Type: "object", Properties: make(map[string]*Schema)}
+		for _, f := range fieldsOf(req, "json") {
+			if !inBody(f.Tag) {
+				continue
+			}
+			prop := s.of(f.Type)
+			if constrain(prop, f.Type, f.Binding) {
+				body.Required = append(body.Required, f.Name)
+			}
+			body.Properties[f.Name] = prop
+		}
+		if len(body.Properties) == 0 {
+			return
+		}
+	}
+	op.RequestBody = &RequestBody{
+		Required: true,
+		Content:  map[string]*MediaType{mimeJSON: {Schema: body}},
+	}
+}
+
+func parameter(s *schemas, f field, in string) *Parameter {
+	sc := s.of(f.Type)
+	required := constrain(sc, f.Type, f.Binding)
+	if f.Default != "" {
+		sc.Default = f.Default
+	}
+	return &Parameter{Name: f.Name, In: in, Required: required || in == "path", Schema: sc}
+}
+
+// inBody 字段带 json 标签且未标记为路径参数或请求头
+func inBody(tag reflect.StructTag) bool {
+	if _, ok := tag.Lookup("json"); !ok {
+		return false
+	}
+	for _, key := range []string{"uri", "header"} {
+		if _, ok := tag.Lookup(key); ok {
+			return false
+		}
+	}
+	return true
+}
+
+// convertPath 将 gin 路径参数 :id 与 *path 转换为 {id}，返回参数名列表
+func convertPath(p string) (string, []string) {
+	segments := strings.Split(p, "/")
+	var params []string
+	for i, seg := range segments {
+		if len(seg) > 1 && (seg[0] == ':' || seg[0] == '*') {
+			params = append(params, seg[1:])
+			segments[i] = "{" + seg[1:] + "}"
+		}
+	}
+	return strings.Join(segments, "/"), params
+}
+
+// operationID 方法名加路径驼峰，如 GET /api/test -> getApiTest，重复时追加序号
+func operationID(method, p string, ids map[string]int) string {
+	var b strings.Builder
+	b.WriteString(strings.ToLower(method))
+	for _, seg := range strings.Split(p, "/") {
+		seg = strings.TrimLeft(seg, ":*")
+		upper := true
+		for _, r := range seg {
+			if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+				upper = true
+				continue
+			}
+			if upper {
+				r = unicode.ToUpper(r)
+				upper = false
+			}
+			b.WriteRune(r)
+		}
+	}
+	id := b.String()
+	ids[id]++
+	if n := ids[id]; n > 1 {
+		id += strconv.Itoa(n)
+	}
+	return id
+}
+
+// tags 以路径首段作为分组
+func tags(p string) []string {
+	for _, seg := range strings.Split(p, "/") {
+		if seg != "" && seg[0] != ':' && seg[0] != '*' {
+			return []string{seg}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package openapi
+
+import (
+	"go-wire/auth"
+	"html"
+	"net/http"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+// redoc 文档页面，规范地址为同级的 openapi.json，脚本由服务本身提供，不依赖外网
+const redoc = `<!DOCTYPE html>
+<html>
+<head>
+  <meta charset="utf-8"/>
+  <title>{{title}}</title>
+  <meta name="viewport" content="width=device-width, initial-scale=1">
+</head>
+<body>
+  <redoc spec-url="openapi.json"></redoc>
+  <script src="docs/redoc.standalone.js" integrity="{{integrity}}"></script>
+</body>
+</html>`
+
+// Register 在 group 下挂载 openapi.json 与 docs 页面
+// 文档在首次请求时根据 routes 生成，此时全部路由均已注册
+func Register(group *gin.RouterGroup, info Info, routes func() gin.RoutesInfo, authz *auth.Authorizer) {
+	specPath := path.Join(group.BasePath(), "openapi.json")
+	docsPath := path.Join(group.BasePath(), "docs")
+	scriptPath := path.Join(docsPath, "redoc.standalone.js")
+
+	var (
+		once sync.Once
+		doc  *Document
+	)
+	group.GET("openapi.json", func(ctx *gin.Context) {
+		once.Do(func() {
+			all := routes()
+			filtered := make(gin.RoutesInfo, 0, len(all))
+			for _, r := range all {
+				if r.Path != specPath && r.Path != docsPath && r.Path != scriptPath {
+					filtered = append(filtered, r)
+				}
+			}
+			doc = Build(info, filtered, authz)
+		})
+		ctx.JSON(http.StatusOK, doc)
+	})
+	page := []byte(strings.NewReplacer(
+		"{{title}}", html.EscapeString(info.Title),
+		"{{integrity}}", redocIntegrity,
+	).Replace(redoc))
+	group.GET("docs", func(ctx *gin.Context) {
+		ctx.Data(http.StatusOK, "text/html; charset=utf-8", page)
+	})
+	group.GET("docs/redoc.standalone.js", func(ctx *gin.Context) {
+		ctx.Header("Cache-Control", "public, max-age=86400")
+		ctx.Data(http.StatusOK, "text/javascript; charset=utf-8", redocJS)
+	})
+}
@@ -0,0 +1,63 @@
+package openapi
+
+import (
+	"bytes"
+	"go-wire/auth"
+	"go-wire/controller"
+	"go-wire/logger"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+type testItemRequest struct {
+	ID    string `uri:"id" binding:"required"`
+	Token string `header:"X-Token"`
+	Page  int    `form:"page"`
+}
+
+type testItem struct {
+	ID string `json:"id"`
+}
+
+func TestBuildTypedRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	authz := auth.NewAuthorizer(logger.NewNop())
+	routes := authz.Group(engine.Group("/api"))
+	c := &controller.Controller{}
+	controller.Register(routes, http.MethodGet, "items/:id", controller.Handle(c, func(*gin.Context, *testItemRequest) (*testItem, error) {
+		return nil, nil
+	}))
+	routes.GET("plain/:name", func(*gin.Context) {})
+
+	doc := Build(Info{Title: "test", Version: "1.0.0"}, engine.Routes(), authz)
+
+	op := (*doc.Paths["/api/items/{id}"])["get"]
+	if op == nil {
+		t.Fatalf("缺少类型化路由: %v", doc.Paths)
+	}
+	in := make(map[string]string)
+	for _, p := range op.Parameters {
+		in[p.Name] = p.In
+	}
+	if in["id"] != "path" || in["X-Token"] != "header" || in["page"] != "query" {
+		t.Errorf("请求参数: %v", in)
+	}
+	if _, ok := doc.Components.Schemas["testItem"]; !ok {
+		t.Errorf("缺少响应结构: %v", doc.Components.Schemas)
+	}
+
+	op = (*doc.Paths["/api/plain/{name}"])["get"]
+	if op == nil || len(op.Parameters) != 1 || op.Parameters[0].Name != "name" {
+		t.Errorf("未注册类型的路由仅生成路径参数: %+v", op)
+	}
+}
+
+// 打包的 redoc 必须是 RedocVersion 对应的正式构建，占位文件不能发布
+func TestRedocBundled(t *testing.T) {
+	if len(redocJS) < 100<<10 || bytes.Contains(redocJS, []byte("go generate ./openapi")) {
+		t.Fatalf("assets/redoc.standalone.js 为占位文件，请执行 go generate ./openapi 打包 redoc %s", RedocVersion)
+	}
+}
@@ -0,0 +1,256 @@
+package openapi
+
+import (
+	"encoding/json"
+	"path"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	timeType       = reflect.TypeFor[time.Time]()
+	rawMessageType = reflect.TypeFor[json.RawMessage]()
+	invalidName    = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+)
+
+// schemas 反射生成 Schema，具名结构体注册到 components
+type schemas struct {
+	defs  map[string]*Schema
+	names map[reflect.Type]string
+}
+
+func newSchemas(defs map[string]*Schema) *schemas {
+	return &schemas{defs: defs, names: make(map[reflect.Type]string)}
+}
+
+func (s *schemas) of(t reflect.Type) *Schema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t {
+	case timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case rawMessageType:
+		return &Schema{}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: s.of(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: s.of(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return s.object(t)
+		}
+		return ref(s.define(t))
+	}
+	// interface 等任意类型
+	return &Schema{}
+}
+
+// define 注册具名结构体并返回组件名，同名不同包时追加包名区分
+func (s *schemas) define(t reflect.Type) string {
+	if name, ok := s.names[t]; ok {
+		return name
+	}
+	name := invalidName.ReplaceAllString(typeName(t), "_")
+	if _, ok := s.defs[name]; ok {
+		name = path.Base(t.PkgPath()) + "." + name
+	}
+	s.names[t] = name
+	// 先占位，支持自引用结构
+	s.defs[name] = &Schema{}
+	*s.defs[name] = *s.object(t)
+	return name
+}
+
+// typeName 泛型类型去掉类型参数的包路径，如 Page[go-wire/controller/dto.TestResponse] -> Page_TestResponse
+func typeName(t reflect.Type) string {
+	name, args, ok := strings.Cut(t.Name(), "[")
+	if !ok {
+		return name
+	}
+	for _, arg := range strings.Split(strings.TrimSuffix(args, "]"), ",") {
+		if i := strings.LastIndex(arg, "."); i != -1 {
+			arg = arg[i+1:]
+		}
+		name += "_" + arg
+	}
+	return name
+}
+
+// object 以 json 标签生成对象属性，binding 标签转换为约束
+func (s *schemas) object(t reflect.Type) *Schema {
+	sc := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	for _, f := range fieldsOf(t, "json") {
+		prop := s.of(f.Type)
+		if constrain(prop, f.Type, f.Binding) {
+			sc.Required = append(sc.Required, f.Name)
+		}
+		sc.Properties[f.Name] = prop
+	}
+	return sc
+}
+
+// field 结构体字段在指定标签下的名称与校验规则
+type field struct {
+	Name    string
+	Type    reflect.Type
+	Tag     reflect.StructTag
+	Binding string
+	Default string
+}
+
+// fieldsOf 按标签展开结构体字段，无标签的匿名字段平铺
+// json 标签缺省时使用字段名，其他标签缺省时忽略该字段
+func fieldsOf(t reflect.Type, tag string) []field {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+	var res []field
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		value, hasTag := sf.Tag.Lookup(tag)
+		name, opts, _ := strings.Cut(value, ",")
+		if name == "-" {
+			continue
+		}
+		ft := sf.Type
+		for ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		if sf.Anonymous && name == "" && ft.Kind() == reflect.Struct {
+			res = append(res, fieldsOf(ft, tag)...)
+			continue
+		}
+		if !sf.IsExported() || (!hasTag && tag != "json") {
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+		f := field{Name: name, Type: sf.Type, Tag: sf.Tag, Binding: sf.Tag.Get("binding")}
+		for _, opt := range strings.Split(opts, ",") {
+			if v, ok := strings.CutPrefix(opt, "default="); ok {
+				f.Default = v
+			}
+		}
+		res = append(res, f)
+	}
+	return res
+}
+
+// constrain 将 binding 规则转换为 Schema 约束，返回是否必填
+func constrain(sc *Schema, t reflect.Type, rules string) bool {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	required := false
+	for _, rule := range strings.Split(rules, ",") {
+		name, param, _ := strings.Cut(rule, "=")
+		if strings.Contains(rule, "|") {
+			continue
+		}
+		switch name {
+		case "required":
+			required = true
+		case "dive":
+			// 之后的规则作用于元素
+			return required
+		case "min", "gte":
+			setBound(sc, t, param, &sc.Minimum, &sc.MinLength, &sc.MinItems)
+		case "max", "lte":
+			setBound(sc, t, param, &sc.Maximum, &sc.MaxLength, &sc.MaxItems)
+		case "len":
+			setBound(sc, t, param, &sc.Minimum, &sc.MinLength, &sc.MinItems)
+			setBound(sc, t, param, &sc.Maximum, &sc.MaxLength, &sc.MaxItems)
+		case "gt":
+			if isNumber(t) {
+				sc.ExclusiveMinimum = parseFloat(param)
+			}
+		case "lt":
+			if isNumber(t) {
+				sc.ExclusiveMaximum = parseFloat(param)
+			}
+		case "oneof":
+			for _, v := range strings.Fields(param) {
+				if n := parseFloat(v); n != nil && isNumber(t) {
+					sc.Enum = append(sc.Enum, *n)
+				} else {
+					sc.Enum = append(sc.Enum, v)
+				}
+			}
+		case "email":
+			sc.Format = "email"
+		case "url", "uri", "http_url":
+			sc.Format = "uri"
+		case "uuid", "uuid4":
+			sc.Format = "uuid"
+		case "ipv4":
+			sc.Format = "ipv4"
+		case "ipv6":
+			sc.Format = "ipv6"
+		}
+	}
+	return required
+}
+
+// setBound 数值类型设置取值范围，字符串设置长度，集合设置元素个数
+func setBound(sc *Schema, t reflect.Type, param string, num **float64, length, items **int) {
+	switch {
+	case isNumber(t):
+		*num = parseFloat(param)
+	case t.Kind() == reflect.String:
+		*length = parseInt(param)
+	case t.Kind() == reflect.Slice, t.Kind() == reflect.Array, t.Kind() == reflect.Map:
+		*items = parseInt(param)
+	}
+}
+
+func isNumber(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+func parseFloat(s string) *float64 {
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return nil
+	}
+	return &v
+}
+
+func parseInt(s string) *int {
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return nil
+	}
+	return &v
+}
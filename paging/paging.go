@@ -0,0 +1,127 @@
+package paging
+
+import (
+	"context"
+	"fmt"
+	"go-wire/errs"
+	"go-wire/i18n"
+	"go-wire/reqctx"
+	"strings"
+)
+
+const (
+	DefaultSize = 20
+	MaxSize     = 100
+	// MaxOffset 页码与偏移游标允许的最大起始偏移，深分页需数据库扫描并丢弃前面的全部行
+	// 更深的数据应通过过滤条件缩小范围
+	MaxOffset = 10000
+)
+
+// Query 列表查询参数，嵌入列表请求 DTO 使用，过滤条件作为 DTO 的其他字段声明
+// 提供 Cursor 时为游标模式，忽略 Page；首次请求不传 Cursor，之后使用响应中的 nextCursor
+type Query struct {
+	Page   int    `json:"page" form:"page" binding:"omitempty,min=1"`
+	Size   int    `json:"size" form:"size" binding:"omitempty,min=1,max=100"`
+	Cursor string `json:"cursor" form:"cursor" binding:"omitempty,max=512"`
+	Sort   string `json:"sort" form:"sort" binding:"omitempty,max=128"` // 逗号分隔，- 前缀表示降序，如 -createdAt,name
+}
+
+// Limit 每页条数，未指定时取默认值
+func (q Query) Limit() int {
+	if q.Size <= 0 {
+		return DefaultSize
+	}
+	return min(q.Size, MaxSize)
+}
+
+// Offset 页码模式的起始偏移，页码过大时截断以避免溢出，超出 MaxOffset 由 Start 报告
+func (q Query) Offset() int {
+	if q.Page <= 1 {
+		return 0
+	}
+	return min(q.Page-1, MaxOffset+1) * q.Limit()
+}
+
+// IsCursor 是否为游标模式
+func (q Query) IsCursor() bool {
+	return q.Cursor != ""
+}
+
+// Order 排序条件，Field 为存储中的列名或键
+type Order struct {
+	Field string
+	Desc  bool
+}
+
+// Sortable 允许排序的字段白名单: 请求中的字段名 -> 存储中的列名或键
+type Sortable map[string]string
+
+// Orders 解析排序参数，字段不在白名单内时返回参数校验错误，未指定时返回 defaults
+func (q Query) Orders(ctx context.Context, allowed Sortable, defaults ...Order) ([]Order, error) {
+	if q.Sort == "" {
+		return defaults, nil
+	}
+	var orders []Order
+	for _, item := range strings.Split(q.Sort, ",") {
+		item = strings.TrimSpace(item)
+		name, desc := strings.CutPrefix(item, "-")
+		name = strings.TrimPrefix(name, "+")
+		field, ok := allowed[name]
+		if !ok {
+			return nil, invalid(ctx, "sort", "sortable", name, "unsupported_sort", "不支持按 %s 排序")
+		}
+		orders = append(orders, Order{Field: field, Desc: desc})
+	}
+	return orders, nil
+}
+
+// Page 分页结果，作为响应信封的 data 输出
+// 页码模式返回 Total 与 Page，NextCursor 为空表示没有更多数据
+type Page[T any] struct {
+	Items      []T    `json:"items"`
+	Total      *int64 `json:"total,omitempty"`
+	Page       int    `json:"page,omitempty"`
+	Size       int    `json:"size"`
+	NextCursor string `json:"nextCursor,omitempty"`
+}
+
+// NewPage 页码模式的分页结果
+func NewPage[T any](items []T, total int64, q Query) *Page[T] {
+	return &Page[T]{Items: nonNil(items), Total: &total, Page: max(q.Page, 1), Size: q.Limit()}
+}
+
+// NewCursorPage 游标模式的分页结果
+func NewCursorPage[T any](items []T, next string, q Query) *Page[T] {
+	return &Page[T]{Items: nonNil(items), Size: q.Limit(), NextCursor: next}
+}
+
+// Map 转换分页结果的元素类型
+func Map[T, R any](p *Page[T], fn func(T) (R, error)) (*Page[R], error) {
+	res := &Page[R]{Items: make([]R, 0, len(p.Items)), Total: p.Total, Page: p.Page, Size: p.Size, NextCursor: p.NextCursor}
+	for _, item := range p.Items {
+		r, err := fn(item)
+		if err != nil {
+			return nil, err
+		}
+		res.Items = append(res.Items, r)
+	}
+	return res, nil
+}
+
+func nonNil[T any](items []T) []T {
+	if items == nil {
+		return []T{}
+	}
+	return items
+}
+
+// invalid 分页参数校验错误，与绑定校验错误的响应结构一致
+func invalid(ctx context.Context, field, rule, param, key, fallback string) error {
+	msg := fmt.Sprintf(i18n.Message(reqctx.Locale(ctx), key, fallback), param)
+	return errs.Invalid.WithDetails([]errs.FieldError{{
+		Field:   errs.Pointer(field),
+		Rule:    rule,
+		Param:   param,
+		Message: msg,
+	}})
+}
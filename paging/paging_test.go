@@ -0,0 +1,70 @@
+package paging
+
+import (
+	"context"
+	"errors"
+	"go-wire/errs"
+	"math"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestQueryStart(t *testing.T) {
+	ctx := context.Background()
+	cases := []struct {
+		name  string
+		query Query
+		start int
+		ok    bool
+	}{
+		{"首页", Query{}, 0, true},
+		{"页码", Query{Page: 3, Size: 10}, 20, true},
+		{"最大偏移", Query{Page: MaxOffset/DefaultSize + 1}, MaxOffset, true},
+		{"超出最大偏移", Query{Page: MaxOffset/DefaultSize + 2}, 0, false},
+		// 页码过大不能溢出为负偏移
+		{"页码溢出", Query{Page: math.MaxInt, Size: MaxSize}, 0, false},
+		{"游标", Query{Cursor: EncodeCursor("40")}, 40, true},
+		{"游标超出最大偏移", Query{Cursor: EncodeCursor(strconv.Itoa(MaxOffset + 1))}, 0, false},
+		{"游标为负", Query{Cursor: EncodeCursor("-1")}, 0, false},
+		{"游标格式错误", Query{Cursor: "!"}, 0, false},
+	}
+	for _, c := range cases {
+		start, err := c.query.Start(ctx)
+		if c.ok && (err != nil || start != c.start) {
+			t.Errorf("%s: start=%d err=%v, want %d", c.name, start, err, c.start)
+		}
+		if !c.ok && !errors.Is(err, errs.Invalid) {
+			t.Errorf("%s: start=%d err=%v, want %v", c.name, start, err, errs.Invalid)
+		}
+	}
+
+	q := Query{Size: 10}
+	if next := q.NextCursor(MaxOffset-10, 10); next == "" {
+		t.Error("下一页未超出最大偏移时应返回游标")
+	}
+	if next := q.NextCursor(MaxOffset-5, 10); next != "" {
+		t.Errorf("下一页超出最大偏移时不返回游标: %s", next)
+	}
+}
+
+func TestWhere(t *testing.T) {
+	type filters struct {
+		Name   string   `filter:"name,like"`
+		Status *int     `filter:"status"`
+		IDs    []string `filter:"id,in"`
+		Min    int      `filter:"age,gte"`
+	}
+	zero := 0
+	where, args := Where(filters{Name: "50%_off!", Status: &zero, IDs: []string{"a", "b"}})
+	if want := "WHERE name LIKE ? ESCAPE '!' AND status = ? AND id IN (?,?)"; where != want {
+		t.Fatalf("where=%q, want %q", where, want)
+	}
+	if want := []any{"%50!%!_off!!%", 0, "a", "b"}; !reflect.DeepEqual(args, want) {
+		t.Fatalf("args=%v, want %v", args, want)
+	}
+
+	if where, args = Where(filters{}); where != "" || args != nil {
+		t.Fatalf("无过滤条件: where=%q args=%v", where, args)
+	}
+}
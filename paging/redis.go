@@ -0,0 +1,106 @@
+package paging
+
+import (
+	"context"
+	"encoding/base64"
+	"strconv"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// EncodeCursor 将存储游标编码为不透明的字符串
+func EncodeCursor(v string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(v))
+}
+
+// Start 本页起始偏移，游标模式下游标为编码后的偏移，超出 MaxOffset 时返回参数校验错误
+func (q Query) Start(ctx context.Context) (int, error) {
+	if !q.IsCursor() {
+		offset := q.Offset()
+		if offset > MaxOffset {
+			return 0, invalid(ctx, "page", "max", strconv.Itoa(q.Page), "page_out_of_range", "页码 %s 超出范围")
+		}
+		return offset, nil
+	}
+	v, err := DecodeCursor(ctx, q.Cursor)
+	if err != nil {
+		return 0, err
+	}
+	offset, err := strconv.Atoi(v)
+	if err != nil || offset < 0 || offset > MaxOffset {
+		return 0, invalid(ctx, "cursor", "cursor", q.Cursor, "invalid_cursor", "游标 %s 无效")
+	}
+	return offset, nil
+}
+
+// NextCursor 偏移游标模式的下一页游标，本页不足 Limit 条或下一页超出 MaxOffset 时返回空
+func (q Query) NextCursor(offset, n int) string {
+	if n < q.Limit() || offset+n > MaxOffset {
+		return ""
+	}
+	return EncodeCursor(strconv.Itoa(offset + n))
+}
+
+// DecodeCursor 解码游标，格式错误时返回参数校验错误
+func DecodeCursor(ctx context.Context, cursor string) (string, error) {
+	v, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return "", invalid(ctx, "cursor", "cursor", cursor, "invalid_cursor", "游标 %s 无效")
+	}
+	return string(v), nil
+}
+
+// ZRange 分页读取有序集合成员，按分数排序，desc 为 true 时降序，页码模式返回总数
+func ZRange(ctx context.Context, client redis.Cmdable, key string, q Query, desc bool) (*Page[string], error) {
+	offset, err := q.Start(ctx)
+	if err != nil {
+		return nil, err
+	}
+	limit := q.Limit()
+	members, err := client.ZRangeArgs(ctx, redis.ZRangeArgs{
+		Key:   key,
+		Start: offset,
+		Stop:  offset + limit - 1,
+		Rev:   desc,
+	}).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	if q.IsCursor() {
+		return NewCursorPage(members, q.NextCursor(offset, len(members)), q), nil
+	}
+	total, err := client.ZCard(ctx, key).Result()
+	if err != nil {
+		return nil, err
+	}
+	page := NewPage(members, total, q)
+	if next := offset + len(members); int64(next) < total && next <= MaxOffset {
+		page.NextCursor = EncodeCursor(strconv.Itoa(next))
+	}
+	return page, nil
+}
+
+// Scan 以游标模式遍历匹配的键，单次返回的数量由 Redis 决定，可能少于或多于 Size
+// 不支持页码与总数，首次请求不传 cursor；cluster 与 ring 模式下 SCAN 仅遍历单个节点，不适用
+func Scan(ctx context.Context, client redis.Cmdable, match string, q Query) (*Page[string], error) {
+	var cursor uint64
+	if q.IsCursor() {
+		v, err := DecodeCursor(ctx, q.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		if cursor, err = strconv.ParseUint(v, 10, 64); err != nil {
+			return nil, invalid(ctx, "cursor", "cursor", q.Cursor, "invalid_cursor", "游标 %s 无效")
+		}
+	}
+	keys, next, err := client.Scan(ctx, cursor, match, int64(q.Limit())).Result()
+	if err != nil {
+		return nil, err
+	}
+	nextCursor := ""
+	if next != 0 {
+		nextCursor = EncodeCursor(strconv.FormatUint(next, 10))
+	}
+	return NewCursorPage(keys, nextCursor, q), nil
+}
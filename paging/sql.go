@@ -0,0 +1,101 @@
+package paging
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Where 根据带 filter 标签的非零字段生成 WHERE 条件，占位符为 ?
+// 标签格式 filter:"column" 或 filter:"column,op"，op: eq(默认)|ne|gt|gte|lt|lte|like|in
+// 零值字段视为未过滤，需要按零值过滤时使用指针类型
+// like 对值中的 % _ 转义后按包含匹配，转义符为 !，反斜杠在 MySQL 字符串字面量中有特殊含义
+func Where(filters any) (string, []any) {
+	v := reflect.ValueOf(filters)
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return "", nil
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return "", nil
+	}
+
+	var (
+		conds []string
+		args  []any
+	)
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		tag, ok := sf.Tag.Lookup("filter")
+		if !ok || tag == "-" || !sf.IsExported() {
+			continue
+		}
+		fv := v.Field(i)
+		if fv.IsZero() {
+			continue
+		}
+		for fv.Kind() == reflect.Pointer {
+			fv = fv.Elem()
+		}
+		column, op, _ := strings.Cut(tag, ",")
+		switch op {
+		case "", "eq":
+			conds, args = append(conds, column+" = ?"), append(args, fv.Interface())
+		case "ne":
+			conds, args = append(conds, column+" <> ?"), append(args, fv.Interface())
+		case "gt":
+			conds, args = append(conds, column+" > ?"), append(args, fv.Interface())
+		case "gte":
+			conds, args = append(conds, column+" >= ?"), append(args, fv.Interface())
+		case "lt":
+			conds, args = append(conds, column+" < ?"), append(args, fv.Interface())
+		case "lte":
+			conds, args = append(conds, column+" <= ?"), append(args, fv.Interface())
+		case "like":
+			conds, args = append(conds, column+" LIKE ? ESCAPE '!'"), append(args, "%"+likeEscaper.Replace(fmt.Sprint(fv.Interface()))+"%")
+		case "in":
+			if fv.Kind() != reflect.Slice || fv.Len() == 0 {
+				continue
+			}
+			conds = append(conds, column+" IN ("+strings.TrimSuffix(strings.Repeat("?,", fv.Len()), ",")+")")
+			for j := 0; j < fv.Len(); j++ {
+				args = append(args, fv.Index(j).Interface())
+			}
+		}
+	}
+	if len(conds) == 0 {
+		return "", nil
+	}
+	return "WHERE " + strings.Join(conds, " AND "), args
+}
+
+var likeEscaper = strings.NewReplacer("!", "!!", "%", "!%", "_", "!_")
+
+// OrderBy 生成 ORDER BY 子句，列名来自 Sortable 白名单，可直接拼接到 SQL
+func OrderBy(orders []Order) string {
+	if len(orders) == 0 {
+		return ""
+	}
+	items := make([]string, 0, len(orders))
+	for _, o := range orders {
+		if o.Desc {
+			items = append(items, o.Field+" DESC")
+		} else {
+			items = append(items, o.Field+" ASC")
+		}
+	}
+	return "ORDER BY " + strings.Join(items, ", ")
+}
+
+// LimitOffset 生成 LIMIT/OFFSET 子句，页码与游标模式均适用，游标模式下用 NextCursor 生成下一页游标
+func LimitOffset(ctx context.Context, q Query) (string, []any, error) {
+	offset, err := q.Start(ctx)
+	if err != nil {
+		return "", nil, err
+	}
+	return "LIMIT ? OFFSET ?", []any{q.Limit(), offset}, nil
+}
@@ -11,21 +11,21 @@ import (
 	"github.com/redis/go-redis/v9"
 )
 
+// Redis 按名称管理 redis 实例，调用方通过 redis.UniversalClient 访问，无需关心实例的部署拓扑
 type Redis struct {
-	Clients map[string]*redis.Client
+	Clients map[string]redis.UniversalClient
 	log     logger.Logger
 }
 
 var ProviderSet = wire.NewSet(NewRedisClients)
 
 func NewRedisClients(cfg *config.Config, log logger.Logger) (*Redis, error) {
-	clients := make(map[string]*redis.Client)
+	clients := make(map[string]redis.UniversalClient)
 	for name, r := range cfg.Redis {
-		rdb := redis.NewClient(&redis.Options{
-			Addr:     r.Addr,
-			Password: r.Password,
-			DB:       r.DB,
-		})
+		rdb, err := newClient(r)
+		if err != nil {
+			return nil, fmt.Errorf("redis %s 配置错误: %w", name, err)
+		}
 		if err := rdb.Ping(context.Background()).Err(); err != nil {
 			return nil, fmt.Errorf("redis %s 连接失败: %w", name, err)
 		}
@@ -34,7 +34,84 @@ func NewRedisClients(cfg *config.Config, log logger.Logger) (*Redis, error) {
 	return &Redis{Clients: clients, log: log}, nil
 }
 
-func (r *Redis) Client(name string) (*redis.Client, error) {
+// newClient 按部署模式创建客户端，命令读写超时以请求 context 的截止时间为准，请求超时后阻塞的命令随之返回
+// sentinel 模式开启 routeByLatency 或 routeRandomly 时使用 FailoverClusterClient，写命令发往主节点，只读命令按策略发往主从节点
+func newClient(r config.RedisConfig) (redis.UniversalClient, error) {
+	readOnly := r.ReadOnly || r.RouteByLatency || r.RouteRandomly
+	switch r.Mode {
+	case "", "single":
+		if r.Addr == "" {
+			return nil, fmt.Errorf("single 模式需要配置 addr")
+		}
+		return redis.NewClient(&redis.Options{
+			Addr:                  r.Addr,
+			Username:              r.Username,
+			Password:              r.Password,
+			DB:                    r.DB,
+			ContextTimeoutEnabled: true,
+		}), nil
+	case "sentinel":
+		if r.MasterName == "" || len(r.SentinelAddrs) == 0 {
+			return nil, fmt.Errorf("sentinel 模式需要配置 masterName 与 sentinelAddrs")
+		}
+		opts := &redis.FailoverOptions{
+			MasterName:            r.MasterName,
+			SentinelAddrs:         r.SentinelAddrs,
+			SentinelPassword:      r.SentinelPassword,
+			Username:              r.Username,
+			Password:              r.Password,
+			DB:                    r.DB,
+			RouteByLatency:        r.RouteByLatency,
+			RouteRandomly:         r.RouteRandomly,
+			ContextTimeoutEnabled: true,
+		}
+		// FailoverOptions 没有 ReadOnly，仅开启 readOnly 时命令仍全部发往主节点
+		if r.ReadOnly && !r.RouteByLatency && !r.RouteRandomly {
+			return nil, fmt.Errorf("sentinel 模式的 readOnly 需同时开启 routeByLatency 或 routeRandomly")
+		}
+		if readOnly {
+			// 连接建立时执行 SELECT，非 0 的 db 同样适用
+			return redis.NewFailoverClusterClient(opts), nil
+		}
+		return redis.NewFailoverClient(opts), nil
+	case "cluster":
+		if len(r.Addrs) == 0 {
+			return nil, fmt.Errorf("cluster 模式需要配置 addrs")
+		}
+		// redis cluster 只有 db 0，不支持 SELECT
+		if r.DB != 0 {
+			return nil, fmt.Errorf("cluster 模式仅支持 db 0")
+		}
+		return redis.NewClusterClient(&redis.ClusterOptions{
+			Addrs:                 r.Addrs,
+			Username:              r.Username,
+			Password:              r.Password,
+			ReadOnly:              readOnly,
+			RouteByLatency:        r.RouteByLatency,
+			RouteRandomly:         r.RouteRandomly,
+			ContextTimeoutEnabled: true,
+		}), nil
+	case "ring":
+		if len(r.Addrs) == 0 {
+			return nil, fmt.Errorf("ring 模式需要配置 addrs")
+		}
+		// 以地址作为分片名，增删分片不影响其余分片的键分布
+		shards := make(map[string]string, len(r.Addrs))
+		for _, addr := range r.Addrs {
+			shards[addr] = addr
+		}
+		return redis.NewRing(&redis.RingOptions{
+			Addrs:                 shards,
+			Username:              r.Username,
+			Password:              r.Password,
+			DB:                    r.DB,
+			ContextTimeoutEnabled: true,
+		}), nil
+	}
+	return nil, fmt.Errorf("不支持的部署模式: %s", r.Mode)
+}
+
+func (r *Redis) Client(name string) (redis.UniversalClient, error) {
 	client, ok := r.Clients[name]
 	if !ok {
 		return nil, fmt.Errorf("redis 实例 [%s] 不存在", name)
@@ -49,7 +126,7 @@ func (r *Redis) Close(ctx context.Context) error {
 
 	for name, client := range r.Clients {
 		wg.Add(1)
-		go func(name string, client *redis.Client) {
+		go func(name string, client redis.UniversalClient) {
 			defer wg.Done()
 			if err := client.Close(); err != nil {
 				r.log.Error(ctx, fmt.Sprintf("关闭 Redis [%s] 失败", name), logger.Error(err))
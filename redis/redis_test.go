@@ -0,0 +1,80 @@
+package redis
+
+import (
+	"context"
+	"go-wire/config"
+	"net"
+	"testing"
+	"time"
+)
+
+// 服务端不响应时，命令在请求 context 到期后返回，而不是等待默认的读超时
+func TestClientHonorsContextDeadline(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			// 读取命令但从不响应
+			go func() {
+				defer conn.Close()
+				buf := make([]byte, 1024)
+				for {
+					if _, err := conn.Read(buf); err != nil {
+						return
+					}
+				}
+			}()
+		}
+	}()
+
+	client, err := newClient(config.RedisConfig{Addr: ln.Addr().String()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if err = client.BLPop(ctx, 0, "queue").Err(); err == nil {
+		t.Fatal("服务端未响应时应返回错误")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("命令在 context 到期 %v 后才返回: %v", elapsed, err)
+	}
+}
+
+func TestNewClientModes(t *testing.T) {
+	cases := []struct {
+		name string
+		cfg  config.RedisConfig
+		ok   bool
+	}{
+		{"single", config.RedisConfig{Addr: "127.0.0.1:6379", DB: 1}, true},
+		{"single 缺少地址", config.RedisConfig{}, false},
+		{"sentinel", config.RedisConfig{Mode: "sentinel", MasterName: "m", SentinelAddrs: []string{"127.0.0.1:26379"}, DB: 1}, true},
+		// 从节点路由通过连接时的 SELECT 切换 db
+		{"sentinel 从节点路由", config.RedisConfig{Mode: "sentinel", MasterName: "m", SentinelAddrs: []string{"127.0.0.1:26379"}, DB: 1, RouteRandomly: true}, true},
+		{"sentinel 仅 readOnly", config.RedisConfig{Mode: "sentinel", MasterName: "m", SentinelAddrs: []string{"127.0.0.1:26379"}, ReadOnly: true}, false},
+		{"cluster", config.RedisConfig{Mode: "cluster", Addrs: []string{"127.0.0.1:7000"}, ReadOnly: true}, true},
+		{"cluster 非 0 db", config.RedisConfig{Mode: "cluster", Addrs: []string{"127.0.0.1:7000"}, DB: 1}, false},
+		{"ring", config.RedisConfig{Mode: "ring", Addrs: []string{"127.0.0.1:6379", "127.0.0.1:6380"}}, true},
+		{"未知模式", config.RedisConfig{Mode: "proxy", Addr: "127.0.0.1:6379"}, false},
+	}
+	for _, c := range cases {
+		client, err := newClient(c.cfg)
+		if (err == nil) != c.ok {
+			t.Errorf("%s: err=%v, want ok=%v", c.name, err, c.ok)
+		}
+		if client != nil {
+			client.Close()
+		}
+	}
+}
@@ -1,10 +1,14 @@
 package repo
 
 import (
+	"context"
+	"errors"
+	"go-wire/errs"
 	"go-wire/logger"
+	"go-wire/paging"
 	"go-wire/redis"
 
-	"github.com/gin-gonic/gin"
+	goredis "github.com/redis/go-redis/v9"
 )
 
 type ApiRepo struct {
@@ -16,16 +20,60 @@ func NewApiRepo(redis *redis.Redis, log logger.Logger) *ApiRepo {
 	return &ApiRepo{redis: redis, log: log}
 }
 
-func (r *ApiRepo) Test(ctx *gin.Context, id string) (string, error) {
+func (r *ApiRepo) Test(ctx context.Context, id string) (string, error) {
 	redisClient, err := r.redis.Client("default")
 	if err != nil {
 		r.log.Error(ctx, "redis nil", logger.Error(err))
 		return "", err
 	}
 	userValue, err := redisClient.Get(ctx, id).Result()
+	if errors.Is(err, goredis.Nil) {
+		return "", errs.UserNotFound.Wrap(err)
+	}
 	if err != nil {
 		r.log.Error(ctx, "未获取到用户", logger.Error(err))
 		return "", err
 	}
 	return userValue, nil
 }
+
+// testIndexKey 按创建时间排序的 id 有序集合
+const testIndexKey = "test:index"
+
+// List 按创建时间分页读取，返回各 id 对应的原始值，已删除的 id 跳过
+func (r *ApiRepo) List(ctx context.Context, q paging.Query, desc bool) (*paging.Page[string], error) {
+	redisClient, err := r.redis.Client("default")
+	if err != nil {
+		r.log.Error(ctx, "redis nil", logger.Error(err))
+		return nil, err
+	}
+	ids, err := paging.ZRange(ctx, redisClient, testIndexKey, q, desc)
+	if err != nil {
+		return nil, err
+	}
+	if len(ids.Items) == 0 {
+		return ids, nil
+	}
+	// 逐个 GET 走管道，cluster 模式下键分布在不同槽位时 MGET 不可用
+	pipe := redisClient.Pipeline()
+	cmds := make([]*goredis.StringCmd, len(ids.Items))
+	for i, id := range ids.Items {
+		cmds[i] = pipe.Get(ctx, id)
+	}
+	// 各命令的错误在下方逐个检查，已删除的 id 返回 redis.Nil
+	_, _ = pipe.Exec(ctx)
+	items := make([]string, 0, len(cmds))
+	for _, cmd := range cmds {
+		v, err := cmd.Result()
+		if errors.Is(err, goredis.Nil) {
+			continue
+		}
+		if err != nil {
+			r.log.Error(ctx, "列表读取失败", logger.Error(err))
+			return nil, err
+		}
+		items = append(items, v)
+	}
+	ids.Items = items
+	return ids, nil
+}
@@ -0,0 +1,21 @@
+package reqctx
+
+import (
+	"context"
+	"go-wire/i18n"
+)
+
+type localeKey struct{}
+
+// WithLocale 将请求语言写入 context
+func WithLocale(ctx context.Context, locale string) context.Context {
+	return context.WithValue(ctx, localeKey{}, locale)
+}
+
+// Locale 读取请求语言，未设置时返回 i18n.Default
+func Locale(ctx context.Context) string {
+	if locale, ok := ctx.Value(localeKey{}).(string); ok && locale != "" {
+		return locale
+	}
+	return i18n.Default
+}
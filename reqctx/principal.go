@@ -0,0 +1,46 @@
+package reqctx
+
+import (
+	"context"
+	"slices"
+)
+
+// Anonymous 匿名主体的认证方式
+const Anonymous = "anonymous"
+
+// Principal 请求主体，由认证中间件写入，service 可据此做数据权限判断而无需依赖 auth
+type Principal struct {
+	ID     string         // 主体标识，如用户 ID、API Key 名称
+	Type   string         // 认证方式: apikey|jwt|hmac|anonymous
+	Roles  []string       // 角色
+	Scopes []string       // 权限范围
+	Claims map[string]any // 原始声明
+
+	Refreshable bool // 凭证由本服务签发，可滑动续期
+}
+
+func (p *Principal) Anonymous() bool {
+	return p.Type == Anonymous
+}
+
+func (p *Principal) HasRole(role string) bool {
+	return slices.Contains(p.Roles, role)
+}
+
+// HasScope 判断是否具备 scope，* 表示全部
+func (p *Principal) HasScope(scope string) bool {
+	return slices.Contains(p.Scopes, scope) || slices.Contains(p.Scopes, "*")
+}
+
+type principalKey struct{}
+
+// WithPrincipal 将请求主体写入 context
+func WithPrincipal(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// PrincipalFrom 读取请求主体，未认证时返回 false
+func PrincipalFrom(ctx context.Context) (*Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(*Principal)
+	return p, ok && p != nil
+}
@@ -0,0 +1,37 @@
+// Package reqctx 请求级数据在 context.Context 中的类型化读写
+// service 与 repo 仅依赖 context.Context，同一业务方法可由 HTTP、定时任务、命令行或 RPC 调用:
+//   - 链路 ID: WithTraceID / TraceID
+//   - 请求主体: WithPrincipal / PrincipalFrom
+//   - 请求语言: WithLocale / Locale
+//   - 截止时间与取消: context.WithTimeout / ctx.Deadline，由超时中间件设置
+package reqctx
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+type traceKey struct{}
+
+// WithTraceID 将链路 ID 写入 context
+func WithTraceID(ctx context.Context, traceID string) context.Context {
+	return context.WithValue(ctx, traceKey{}, traceID)
+}
+
+// TraceID 读取链路 ID，未设置时返回空字符串
+func TraceID(ctx context.Context) string {
+	traceID, _ := ctx.Value(traceKey{}).(string)
+	return traceID
+}
+
+// Background 非请求场景(定时任务、命令行)的根 context，以 name 与启动时间作为链路 ID
+func Background(name string) context.Context {
+	return WithTraceID(context.Background(), fmt.Sprintf("%s:date:%s", name, time.Now().Format("2006-01-02 15:04:05")))
+}
+
+// Detach 保留链路 ID、主体与语言等请求数据，去掉截止时间与取消信号
+// 用于请求返回后仍需继续执行的异步任务
+func Detach(ctx context.Context) context.Context {
+	return context.WithoutCancel(ctx)
+}
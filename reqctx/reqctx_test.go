@@ -0,0 +1,40 @@
+package reqctx
+
+import (
+	"context"
+	"go-wire/i18n"
+	"testing"
+	"time"
+)
+
+func TestDetach(t *testing.T) {
+	ctx := WithTraceID(context.Background(), "t1")
+	ctx = WithPrincipal(ctx, &Principal{ID: "u1", Type: "jwt"})
+	ctx = WithLocale(ctx, i18n.EN)
+	ctx, cancel := context.WithTimeout(ctx, time.Minute)
+	cancel()
+
+	detached := Detach(ctx)
+	if detached.Err() != nil {
+		t.Fatalf("分离后不应继承取消: %v", detached.Err())
+	}
+	if _, ok := detached.Deadline(); ok {
+		t.Fatal("分离后不应继承截止时间")
+	}
+	if p, ok := PrincipalFrom(detached); !ok || p.ID != "u1" || p.Anonymous() {
+		t.Fatalf("请求主体: %+v", p)
+	}
+	if TraceID(detached) != "t1" || Locale(detached) != i18n.EN {
+		t.Fatalf("链路 ID 或语言丢失: %s %s", TraceID(detached), Locale(detached))
+	}
+}
+
+func TestDefaults(t *testing.T) {
+	ctx := context.Background()
+	if _, ok := PrincipalFrom(ctx); ok {
+		t.Fatal("未认证时不应返回主体")
+	}
+	if Locale(ctx) != i18n.Default {
+		t.Fatalf("未设置语言时返回默认语言: %s", Locale(ctx))
+	}
+}
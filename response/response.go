@@ -0,0 +1,103 @@
+package response
+
+import (
+	"go-wire/codec"
+	"go-wire/constant"
+	"go-wire/errs"
+	"go-wire/i18n"
+	"go-wire/reqctx"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CodecKey 本次响应协商出的编解码器名称，供日志输出
+const CodecKey = "codec"
+
+// Result 统一响应出口，所有响应体均为 constant.Response，按 Accept 协商编码格式
+// 数据无法以协商格式编码时(如 protobuf 遇到非 proto 数据)回退为 JSON
+func Result(ctx *gin.Context, status, code int, msg string, data any) {
+	c, raw, fallback, err := encode(ctx.GetHeader("Accept"), constant.Response{
+		Code: code,
+		Msg:  msg,
+		Data: data,
+	})
+	if fallback != nil {
+		_ = ctx.Error(fallback)
+	}
+	ctx.Writer.Header().Add("Vary", "Accept")
+	if err != nil {
+		_ = ctx.Error(err)
+		ctx.AbortWithStatus(http.StatusInternalServerError)
+		return
+	}
+	ctx.Set(CodecKey, c.Name())
+	ctx.Data(status, c.ContentType(), raw)
+}
+
+// WriteError 不经过 gin.Context 直接输出错误响应，带 Content-Length，Flush 后客户端即可读取完整响应
+// 用于处理函数仍在其他协程中使用 gin.Context 的场景，如请求超时
+func WriteError(w http.ResponseWriter, req *http.Request, err error) {
+	e := errs.From(err)
+	c, raw, _, encErr := encode(req.Header.Get("Accept"), constant.Response{
+		Code: e.Code,
+		Msg:  message(reqctx.Locale(req.Context()), e),
+		Data: e.Details,
+	})
+	w.Header().Add("Vary", "Accept")
+	if encErr != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", c.ContentType())
+	w.Header().Set("Content-Length", strconv.Itoa(len(raw)))
+	w.WriteHeader(e.Status)
+	_, _ = w.Write(raw)
+}
+
+// encode 按 Accept 编码响应体，协商格式编码失败时回退为 JSON，fallback 为回退原因
+func encode(accept string, body constant.Response) (c codec.Codec, raw []byte, fallback, err error) {
+	c = codec.Negotiate(accept)
+	raw, err = c.Marshal(body)
+	if err != nil && c.Name() != codec.JSON {
+		fallback = err
+		c = codec.Default()
+		raw, err = c.Marshal(body)
+	}
+	if err == nil {
+		codec.Record("response", c.Name())
+	}
+	return c, raw, fallback, err
+}
+
+func Success(ctx *gin.Context, data any) {
+	Result(ctx, http.StatusOK, constant.SUCCESS, i18n.Message(locale(ctx), "success", "请求成功"), data)
+}
+
+// Error 按业务错误的状态码与响应码输出并中断后续处理
+// 原始错误记录到 ctx.Errors 供日志中间件输出
+func Error(ctx *gin.Context, err error) {
+	if err == nil {
+		err = errs.Internal
+	}
+	e := errs.From(err)
+	_ = ctx.Error(err)
+	ctx.Abort()
+	Result(ctx, e.Status, e.Code, message(locale(ctx), e), e.Details)
+}
+
+// message 业务错误的本地化提示，通过 WithMsg 自定义的提示原样返回
+func message(locale string, e *errs.Error) string {
+	if registered, ok := errs.Lookup(e.Code); ok && registered.Msg == e.Msg {
+		return i18n.Message(locale, e.Key, e.Msg)
+	}
+	return e.Msg
+}
+
+func locale(ctx *gin.Context) string {
+	if ctx.Request == nil {
+		return i18n.Default
+	}
+	return reqctx.Locale(ctx.Request.Context())
+}
@@ -0,0 +1,68 @@
+package router
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Builder 路由构建器，recovery 与 trace 固定在最外层，其余中间件按声明顺序执行
+type Builder struct {
+	engine *gin.Engine
+}
+
+// NewBuilder trustedProxies 为可信代理，仅信任其转发的 X-Forwarded-For，为空时 ClientIP 取连接地址
+func NewBuilder(trustedProxies []string, recovery, trace gin.HandlerFunc) (*Builder, error) {
+	engine := gin.New()
+	// gin.Context 作为 context.Context 使用时回退到 Request.Context()
+	engine.ContextWithFallback = true
+	if err := engine.SetTrustedProxies(trustedProxies); err != nil {
+		return nil, fmt.Errorf("可信代理配置错误: %w", err)
+	}
+	engine.Use(recovery, trace)
+	return &Builder{engine: engine}, nil
+}
+
+// Use 追加全局中间件，作用于所有路由(包括 404)
+func (b *Builder) Use(handlers ...gin.HandlerFunc) *Builder {
+	b.engine.Use(handlers...)
+	return b
+}
+
+// Group 创建路由组，handlers 为该组独有的中间件链
+func (b *Builder) Group(relativePath string, handlers ...gin.HandlerFunc) *gin.RouterGroup {
+	return b.engine.Group(relativePath, handlers...)
+}
+
+func (b *Builder) Engine() *gin.Engine {
+	return b.engine
+}
+
+// Skip 请求路径命中 paths 时跳过 handler，/prefix/* 表示前缀匹配
+func Skip(paths []string, handler gin.HandlerFunc) gin.HandlerFunc {
+	exact := make(map[string]struct{}, len(paths))
+	prefixes := make([]string, 0, len(paths))
+	for _, p := range paths {
+		if prefix, ok := strings.CutSuffix(p, "*"); ok {
+			prefixes = append(prefixes, prefix)
+		} else {
+			exact[p] = struct{}{}
+		}
+	}
+
+	return func(ctx *gin.Context) {
+		path := ctx.Request.URL.Path
+		if _, ok := exact[path]; ok {
+			ctx.Next()
+			return
+		}
+		for _, prefix := range prefixes {
+			if strings.HasPrefix(path, prefix) {
+				ctx.Next()
+				return
+			}
+		}
+		handler(ctx)
+	}
+}
@@ -1,34 +1,59 @@
 package middleware
 
 import (
-	"go-wire/config"
-	"go-wire/constant"
+	"go-wire/auth"
+	"go-wire/errs"
 	"go-wire/logger"
+	"go-wire/reqctx"
+	"go-wire/response"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 )
 
 type AuthMiddleware struct {
-	cfg *config.Config
-	log logger.Logger
+	manager *auth.Manager
+	log     logger.Logger
 }
 
-func NewAuthMiddleware(cfg *config.Config, log logger.Logger) *AuthMiddleware {
-	return &AuthMiddleware{cfg: cfg, log: log}
+func NewAuthMiddleware(manager *auth.Manager, log logger.Logger) *AuthMiddleware {
+	return &AuthMiddleware{manager: manager, log: log}
 }
 
 func (m *AuthMiddleware) Handler() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
-		clientID := ctx.Request.Header.Get("clientId")
-		if clientID != m.cfg.App.ClientID {
-			m.log.Warn(ctx, "无权限", logger.StringAny("clientId", clientID))
-			ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
-				"code": constant.FORBIDDEN,
-				"msg":  "无权限",
-			})
+		// 跨域预检请求不携带认证信息
+		if ctx.Request.Method == http.MethodOptions {
+			ctx.Next()
 			return
 		}
+
+		principal, err := m.manager.Authenticate(ctx.Request)
+		if err != nil {
+			m.log.Warn(ctx, "无权限",
+				logger.StringAny("url", ctx.Request.URL.Path),
+				logger.Error(err),
+			)
+			response.Error(ctx, errs.Unauthorized.Wrap(err))
+			return
+		}
+
+		ctx.Request = ctx.Request.WithContext(reqctx.WithPrincipal(ctx.Request.Context(), principal))
+		m.refresh(ctx, principal)
 		ctx.Next()
 	}
 }
+
+// refresh 滑动续期，通过 New-Token/New-Expires-At 响应头下发新 token
+func (m *AuthMiddleware) refresh(ctx *gin.Context, principal *auth.Principal) {
+	token, expiresAt, ok, err := m.manager.Refresh(principal)
+	if err != nil {
+		m.log.Warn(ctx, "token 续期失败", logger.Error(err))
+		return
+	}
+	if ok {
+		ctx.Header("New-Token", token)
+		ctx.Header("New-Expires-At", strconv.FormatInt(expiresAt.Unix(), 10))
+	}
+}
@@ -0,0 +1,273 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"go-wire/cache"
+	"go-wire/config"
+	"go-wire/reqctx"
+	"net/http"
+	"net/textproto"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const cacheKeyPrefix = "cache:"
+
+var (
+	defaultCacheHeaders = []string{"Accept", "Accept-Language"}
+	tagParam            = regexp.MustCompile(`\{(\w+)\}`)
+)
+
+type CacheMiddleware struct {
+	cache  *cache.Cache
+	routes map[string]cacheRoute
+}
+
+type cacheRoute struct {
+	ttl     time.Duration
+	tags    []string
+	headers []string
+	shared  bool
+}
+
+func NewCacheMiddleware(cfg *config.Config, c *cache.Cache) *CacheMiddleware {
+	headers := cfg.Cache.Headers
+	if len(headers) == 0 {
+		headers = defaultCacheHeaders
+	}
+	m := &CacheMiddleware{cache: c, routes: make(map[string]cacheRoute, len(cfg.Cache.Routes))}
+	for _, r := range cfg.Cache.Routes {
+		if r.TTL <= 0 {
+			continue
+		}
+		route := cacheRoute{ttl: r.TTL, tags: r.Tags, headers: r.Headers, shared: r.Shared}
+		if len(route.headers) == 0 {
+			route.headers = headers
+		}
+		m.routes[r.Path] = route
+	}
+	return m
+}
+
+// Handler 缓存 GET 请求的 200 响应，响应带强 ETag，If-None-Match 命中时返回 304
+// 请求 Cache-Control: no-store 跳过缓存，no-cache 跳过读取但更新缓存，max-age 限制可接受的缓存时长
+// 处理函数设置 Cache-Control: no-store 的响应不缓存
+func (m *CacheMiddleware) Handler() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		route, ok := m.routes[ctx.FullPath()]
+		if !ok || ctx.Request.Method != http.MethodGet {
+			ctx.Next()
+			return
+		}
+		directives := cacheControl(ctx.GetHeader("Cache-Control"))
+		if _, ok := directives["no-store"]; ok {
+			ctx.Next()
+			return
+		}
+
+		key := m.key(ctx, route)
+		if _, noCache := directives["no-cache"]; !noCache {
+			if e, ok := m.cache.Get(ctx, key); ok && acceptable(e, directives) {
+				m.serve(ctx, e, route, "HIT")
+				return
+			}
+		}
+
+		before := ctx.Writer.Header().Clone()
+		w := &bufferWriter{ResponseWriter: ctx.Writer, status: http.StatusOK}
+		ctx.Writer = w
+		defer func() {
+			// 处理函数 panic 时恢复 writer，由 recovery 输出错误响应
+			ctx.Writer = w.ResponseWriter
+		}()
+		ctx.Next()
+		ctx.Writer = w.ResponseWriter
+
+		_, noStore := cacheControl(w.Header().Get("Cache-Control"))["no-store"]
+		if w.status != http.StatusOK || noStore {
+			w.flush()
+			return
+		}
+		body := w.body.Bytes()
+		sum := sha256.Sum256(body)
+		e := &cache.Entry{
+			Status:   w.status,
+			Header:   changedHeader(before, w.Header()),
+			Body:     body,
+			ETag:     `"` + hex.EncodeToString(sum[:16]) + `"`,
+			Tags:     m.tags(ctx, route),
+			StoredAt: time.Now(),
+			TTL:      route.ttl,
+		}
+		m.cache.Set(ctx, key, e)
+		m.serve(ctx, e, route, "MISS")
+	}
+}
+
+// key 由路径、排序后的查询参数、指定请求头与请求主体组成
+func (m *CacheMiddleware) key(ctx *gin.Context, route cacheRoute) string {
+	var b strings.Builder
+	b.WriteString(ctx.Request.URL.Path)
+	b.WriteByte('?')
+	query := ctx.Request.URL.Query()
+	for _, values := range query {
+		sort.Strings(values)
+	}
+	b.WriteString(query.Encode())
+	for _, name := range route.headers {
+		b.WriteString("\n" + textproto.CanonicalMIMEHeaderKey(name) + ":" + strings.Join(ctx.Request.Header.Values(name), ","))
+	}
+	if !route.shared {
+		if p, ok := reqctx.PrincipalFrom(ctx.Request.Context()); ok && !p.Anonymous() {
+			b.WriteString("\n" + p.Type + ":" + p.ID)
+		}
+	}
+	sum := sha256.Sum256([]byte(b.String()))
+	return cacheKeyPrefix + hex.EncodeToString(sum[:])
+}
+
+// tags 替换标签中的 {name} 为路径参数或查询参数
+func (m *CacheMiddleware) tags(ctx *gin.Context, route cacheRoute) []string {
+	res := make([]string, 0, len(route.tags))
+	for _, tag := range route.tags {
+		res = append(res, tagParam.ReplaceAllStringFunc(tag, func(s string) string {
+			name := s[1 : len(s)-1]
+			if v := ctx.Param(name); v != "" {
+				return v
+			}
+			return ctx.Query(name)
+		}))
+	}
+	return res
+}
+
+// serve 输出缓存的响应，已由前置中间件设置的响应头不重复添加
+func (m *CacheMiddleware) serve(ctx *gin.Context, e *cache.Entry, route cacheRoute, state string) {
+	h := ctx.Writer.Header()
+	for k, values := range e.Header {
+		for _, v := range values {
+			if !slices.Contains(h.Values(k), v) {
+				h.Add(k, v)
+			}
+		}
+	}
+	scope := "private"
+	if route.shared {
+		scope = "public"
+	}
+	h.Set("ETag", e.ETag)
+	h.Set("Cache-Control", scope+", max-age="+strconv.Itoa(int(e.Remaining().Round(time.Second).Seconds())))
+	h.Set("Age", strconv.Itoa(int(e.Age().Seconds())))
+	h.Set("X-Cache", state)
+	ctx.Abort()
+
+	if etagMatch(ctx.GetHeader("If-None-Match"), e.ETag) {
+		h.Del("Content-Length")
+		ctx.Writer.WriteHeader(http.StatusNotModified)
+		ctx.Writer.WriteHeaderNow()
+		return
+	}
+	ctx.Writer.WriteHeader(e.Status)
+	_, _ = ctx.Writer.Write(e.Body)
+}
+
+// etagMatch If-None-Match 使用弱比较，压缩中间件会将强 ETag 改为弱 ETag
+func etagMatch(header, etag string) bool {
+	if header == "" {
+		return false
+	}
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// cacheControl 解析 Cache-Control 指令，指令名小写
+func cacheControl(header string) map[string]string {
+	res := make(map[string]string)
+	for _, part := range strings.Split(header, ",") {
+		name, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		if name != "" {
+			res[strings.ToLower(name)] = strings.Trim(value, `"`)
+		}
+	}
+	return res
+}
+
+// acceptable 请求 max-age 限制可接受的缓存时长
+func acceptable(e *cache.Entry, directives map[string]string) bool {
+	v, ok := directives["max-age"]
+	if !ok {
+		return true
+	}
+	seconds, err := strconv.Atoi(v)
+	return err == nil && e.Age() <= time.Duration(seconds)*time.Second
+}
+
+// bufferWriter 缓冲响应，由调用方决定原样写出或生成 ETag 后写出
+type bufferWriter struct {
+	gin.ResponseWriter
+	status      int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+func (w *bufferWriter) WriteHeader(code int) {
+	if code > 0 && !w.wroteHeader {
+		w.status = code
+	}
+}
+
+func (w *bufferWriter) WriteHeaderNow() {
+	w.wroteHeader = true
+}
+
+func (w *bufferWriter) Write(p []byte) (int, error) {
+	w.wroteHeader = true
+	return w.body.Write(p)
+}
+
+func (w *bufferWriter) WriteString(s string) (int, error) {
+	w.wroteHeader = true
+	return w.body.WriteString(s)
+}
+
+func (w *bufferWriter) Status() int {
+	return w.status
+}
+
+func (w *bufferWriter) Size() int {
+	if !w.wroteHeader {
+		return -1
+	}
+	return w.body.Len()
+}
+
+func (w *bufferWriter) Written() bool {
+	return w.wroteHeader
+}
+
+// Flush 缓存路由的响应在处理完成后统一写出
+func (w *bufferWriter) Flush() {}
+
+// flush 原样写出缓冲的响应
+func (w *bufferWriter) flush() {
+	w.ResponseWriter.WriteHeader(w.status)
+	if w.wroteHeader {
+		w.ResponseWriter.WriteHeaderNow()
+	}
+	if w.body.Len() > 0 {
+		_, _ = w.ResponseWriter.Write(w.body.Bytes())
+	}
+}
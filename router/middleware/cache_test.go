@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"go-wire/auth"
+	"go-wire/cache"
+	"go-wire/config"
+	"go-wire/logger"
+	"go-wire/reqctx"
+	"go-wire/response"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 缓存命中不能绕过路由授权: 共享缓存已预热时，缺少 scope 的主体仍返回 403
+func TestCacheHitRequiresAuthorization(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cfg := &config.Config{}
+	cfg.Cache = config.CacheConfig{Routes: []config.CacheRoute{{Path: "/api/test", TTL: time.Minute, Shared: true}}}
+	c, err := cache.NewCache(cfg, logger.NewNop(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	engine := gin.New()
+	group := engine.Group("/", func(ctx *gin.Context) {
+		p := &reqctx.Principal{ID: ctx.GetHeader("X-User"), Type: "jwt"}
+		if scopes := ctx.GetHeader("X-Scopes"); scopes != "" {
+			p.Scopes = strings.Split(scopes, ",")
+		}
+		ctx.Request = ctx.Request.WithContext(reqctx.WithPrincipal(ctx.Request.Context(), p))
+	})
+	routes := auth.NewAuthorizer(logger.NewNop()).Group(group).Use(NewCacheMiddleware(cfg, c).Handler())
+	calls := 0
+	routes.With(auth.RequireScopes("api:read")).GET("api/test", func(ctx *gin.Context) {
+		calls++
+		response.Success(ctx, "ok")
+	})
+
+	get := func(user, scopes string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/test", nil)
+		req.Header.Set("X-User", user)
+		req.Header.Set("X-Scopes", scopes)
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, req)
+		return w
+	}
+
+	if w := get("reader", "api:read"); w.Code != http.StatusOK || w.Header().Get("X-Cache") != "MISS" {
+		t.Fatalf("预热请求: status=%d X-Cache=%q", w.Code, w.Header().Get("X-Cache"))
+	}
+	if w := get("reader", "api:read"); w.Header().Get("X-Cache") != "HIT" {
+		t.Fatalf("缓存未命中: X-Cache=%q", w.Header().Get("X-Cache"))
+	}
+	for _, user := range []string{"other", "reader"} {
+		w := get(user, "")
+		if w.Code != http.StatusForbidden {
+			t.Fatalf("%s 缺少 scope: status=%d, want %d", user, w.Code, http.StatusForbidden)
+		}
+		if w.Header().Get("X-Cache") != "" {
+			t.Fatalf("%s 缺少 scope 仍读取了缓存: X-Cache=%q", user, w.Header().Get("X-Cache"))
+		}
+	}
+	if calls != 1 {
+		t.Fatalf("处理函数执行 %d 次, want 1", calls)
+	}
+}
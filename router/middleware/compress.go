@@ -0,0 +1,344 @@
+package middleware
+
+import (
+	"fmt"
+	"go-wire/config"
+	"go-wire/errs"
+	"go-wire/response"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/andybalholm/brotli"
+	"github.com/gin-gonic/gin"
+	"github.com/klauspost/compress/gzip"
+	"github.com/klauspost/compress/zstd"
+)
+
+const (
+	defaultCompressMinSize = 1024
+	defaultMaxDecompressed = 10 << 20
+)
+
+var (
+	defaultEncodings = []string{"zstd", "br", "gzip"}
+	// defaultExcludeTypes 已压缩的内容类型，再次压缩收益很小
+	defaultExcludeTypes = []string{
+		"image/", "video/", "audio/", "font/woff",
+		"application/zip", "application/gzip", "application/x-gzip", "application/zstd",
+		"application/x-7z-compressed", "application/x-rar-compressed",
+	}
+)
+
+// encoder 可复用的压缩编码器
+type encoder interface {
+	io.WriteCloser
+	Flush() error
+	Reset(w io.Writer)
+}
+
+// encoderPools 按编码复用编码器，避免每个请求重新分配压缩窗口
+var encoderPools = map[string]*sync.Pool{
+	"gzip": {New: func() any {
+		w, _ := gzip.NewWriterLevel(io.Discard, gzip.DefaultCompression)
+		return w
+	}},
+	"zstd": {New: func() any {
+		w, _ := zstd.NewWriter(io.Discard, zstd.WithEncoderConcurrency(1))
+		return w
+	}},
+	"br": {New: func() any {
+		return brotli.NewWriterLevel(io.Discard, brotli.DefaultCompression)
+	}},
+}
+
+type CompressMiddleware struct {
+	enabled         bool
+	encodings       []string
+	minSize         int
+	exclude         []string
+	decompress      bool
+	maxDecompressed int64
+}
+
+func NewCompressMiddleware(cfg *config.Config) (*CompressMiddleware, error) {
+	c := cfg.Compress
+	m := &CompressMiddleware{
+		enabled:         c.Enabled,
+		encodings:       c.Encodings,
+		minSize:         c.MinSize,
+		exclude:         c.ExcludeTypes,
+		decompress:      c.Decompress,
+		maxDecompressed: c.MaxDecompressed,
+	}
+	if len(m.encodings) == 0 {
+		m.encodings = defaultEncodings
+	}
+	for _, enc := range m.encodings {
+		if _, ok := encoderPools[enc]; !ok {
+			return nil, fmt.Errorf("不支持的压缩编码: %s", enc)
+		}
+	}
+	if m.minSize <= 0 {
+		m.minSize = defaultCompressMinSize
+	}
+	if len(m.exclude) == 0 {
+		m.exclude = defaultExcludeTypes
+	}
+	if m.maxDecompressed <= 0 {
+		m.maxDecompressed = defaultMaxDecompressed
+	}
+	return m, nil
+}
+
+// Handler 按 Accept-Encoding 压缩响应，按 Content-Encoding 解压请求体
+func (m *CompressMiddleware) Handler() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if m.decompress {
+			body, err := m.decodeRequest(ctx)
+			if err != nil {
+				response.Error(ctx, errs.BadRequest.Wrap(err))
+				return
+			}
+			if body != nil {
+				defer body.Close()
+			}
+		}
+		if !m.enabled {
+			ctx.Next()
+			return
+		}
+
+		ctx.Writer.Header().Add("Vary", "Accept-Encoding")
+		encoding := m.negotiate(ctx.GetHeader("Accept-Encoding"))
+		if encoding == "" || ctx.Request.Method == http.MethodHead {
+			ctx.Next()
+			return
+		}
+
+		w := &compressWriter{ResponseWriter: ctx.Writer, m: m, encoding: encoding}
+		ctx.Writer = w
+		completed := false
+		defer func() {
+			ctx.Writer = w.ResponseWriter
+			if completed {
+				w.Close()
+			} else {
+				// 处理函数 panic，丢弃缓冲内容由 recovery 输出错误响应
+				w.release()
+			}
+		}()
+		ctx.Next()
+		completed = true
+	}
+}
+
+// negotiate 选出客户端可接受且权重最高的编码，权重相同时按配置顺序优先
+func (m *CompressMiddleware) negotiate(header string) string {
+	if header == "" {
+		return ""
+	}
+	weights := make(map[string]float64)
+	for _, part := range strings.Split(header, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "x-gzip" {
+			name = "gzip"
+		}
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if parsed, err := strconv.ParseFloat(v, 64); err == nil {
+				q = parsed
+			}
+		}
+		weights[name] = q
+	}
+	best, bestQ := "", 0.0
+	for _, enc := range m.encodings {
+		q, ok := weights[enc]
+		if !ok {
+			q, ok = weights["*"]
+		}
+		if ok && q > bestQ {
+			best, bestQ = enc, q
+		}
+	}
+	return best
+}
+
+// decodeRequest 解压请求体并限制解压后的大小，超出时读取请求体返回 *http.MaxBytesError
+// 未识别的编码保持原样交由处理函数处理
+func (m *CompressMiddleware) decodeRequest(ctx *gin.Context) (io.Closer, error) {
+	req := ctx.Request
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	var body io.ReadCloser
+	switch strings.ToLower(strings.TrimSpace(req.Header.Get("Content-Encoding"))) {
+	case "gzip", "x-gzip":
+		r, err := gzip.NewReader(req.Body)
+		if err != nil {
+			return nil, err
+		}
+		body = r
+	case "zstd":
+		r, err := zstd.NewReader(req.Body,
+			zstd.WithDecoderConcurrency(1),
+			zstd.WithDecoderMaxMemory(uint64(m.maxDecompressed)),
+		)
+		if err != nil {
+			return nil, err
+		}
+		body = r.IOReadCloser()
+	case "br":
+		body = io.NopCloser(brotli.NewReader(req.Body))
+	default:
+		return nil, nil
+	}
+	req.Body = http.MaxBytesReader(ctx.Writer, body, m.maxDecompressed)
+	req.Header.Del("Content-Encoding")
+	req.Header.Del("Content-Length")
+	req.ContentLength = -1
+	return body, nil
+}
+
+// compressWriter 缓冲响应直到达到最小压缩大小，Flush 时立即开始流式压缩
+type compressWriter struct {
+	gin.ResponseWriter
+	m        *CompressMiddleware
+	encoding string
+	buf      []byte
+	started  bool
+	enc      encoder
+}
+
+func (w *compressWriter) Write(p []byte) (int, error) {
+	if w.started {
+		if w.enc != nil {
+			return w.enc.Write(p)
+		}
+		return w.ResponseWriter.Write(p)
+	}
+	w.buf = append(w.buf, p...)
+	if len(w.buf) >= w.m.minSize {
+		w.start(true)
+		if err := w.flushBuffer(); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+func (w *compressWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// WriteHeaderNow 立即输出响应头，此时尚无响应体，仅流式类型开启压缩
+func (w *compressWriter) WriteHeaderNow() {
+	if !w.started {
+		w.start(strings.HasPrefix(w.Header().Get("Content-Type"), "text/event-stream"))
+	}
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+// Flush 流式响应(SSE 等)不等待最小大小，压缩已写入内容后立即下发
+func (w *compressWriter) Flush() {
+	if !w.started {
+		w.start(true)
+		if err := w.flushBuffer(); err != nil {
+			return
+		}
+	}
+	if w.enc != nil {
+		_ = w.enc.Flush()
+	}
+	w.ResponseWriter.Flush()
+}
+
+func (w *compressWriter) Written() bool {
+	return len(w.buf) > 0 || w.ResponseWriter.Written()
+}
+
+func (w *compressWriter) Size() int {
+	if !w.started && len(w.buf) > 0 {
+		return len(w.buf)
+	}
+	return w.ResponseWriter.Size()
+}
+
+// Close 输出剩余内容并归还编码器，未达到最小大小的响应原样输出
+func (w *compressWriter) Close() {
+	if !w.started {
+		w.start(false)
+	}
+	_ = w.flushBuffer()
+	if w.enc != nil {
+		_ = w.enc.Close()
+	}
+	w.release()
+}
+
+func (w *compressWriter) release() {
+	if w.enc != nil {
+		w.enc.Reset(io.Discard)
+		encoderPools[w.encoding].Put(w.enc)
+		w.enc = nil
+	}
+	w.buf = nil
+}
+
+// start 确定是否压缩，压缩时设置 Content-Encoding 并移除 Content-Length
+func (w *compressWriter) start(compress bool) {
+	w.started = true
+	if !compress || !w.compressible() {
+		return
+	}
+	h := w.Header()
+	if h.Get("Content-Type") == "" && len(w.buf) > 0 {
+		h.Set("Content-Type", http.DetectContentType(w.buf))
+	}
+	h.Set("Content-Encoding", w.encoding)
+	h.Del("Content-Length")
+	// 强 ETag 对应未压缩内容，压缩后改为弱 ETag
+	if etag := h.Get("ETag"); etag != "" && !strings.HasPrefix(etag, "W/") {
+		h.Set("ETag", "W/"+etag)
+	}
+	w.enc = encoderPools[w.encoding].Get().(encoder)
+	w.enc.Reset(w.ResponseWriter)
+}
+
+// compressible 无响应体的状态码、分段响应、已编码或排除的内容类型不压缩
+func (w *compressWriter) compressible() bool {
+	status := w.Status()
+	if status < http.StatusOK || status == http.StatusNoContent ||
+		status == http.StatusPartialContent || status == http.StatusNotModified {
+		return false
+	}
+	h := w.Header()
+	if h.Get("Content-Encoding") != "" {
+		return false
+	}
+	contentType := h.Get("Content-Type")
+	for _, prefix := range w.m.exclude {
+		if strings.HasPrefix(contentType, prefix) {
+			return false
+		}
+	}
+	return true
+}
+
+func (w *compressWriter) flushBuffer() error {
+	if len(w.buf) == 0 {
+		return nil
+	}
+	buf := w.buf
+	w.buf = nil
+	if w.enc != nil {
+		_, err := w.enc.Write(buf)
+		return err
+	}
+	_, err := w.ResponseWriter.Write(buf)
+	return err
+}
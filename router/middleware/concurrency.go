@@ -0,0 +1,213 @@
+package middleware
+
+import (
+	"container/list"
+	"context"
+	"go-wire/config"
+	"go-wire/errs"
+	"go-wire/logger"
+	"go-wire/response"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const defaultQueueTimeout = time.Second
+
+type ConcurrencyMiddleware struct {
+	log          logger.Logger
+	global       *concurrencyLimiter
+	routes       map[string]*concurrencyLimiter
+	queueTimeout time.Duration
+}
+
+func NewConcurrencyMiddleware(cfg *config.Config, log logger.Logger) *ConcurrencyMiddleware {
+	c := cfg.Concurrency
+	m := &ConcurrencyMiddleware{
+		log:          log,
+		routes:       make(map[string]*concurrencyLimiter, len(c.Routes)),
+		queueTimeout: c.QueueTimeout,
+	}
+	if m.queueTimeout <= 0 {
+		m.queueTimeout = defaultQueueTimeout
+	}
+	if c.Max > 0 {
+		m.global = newConcurrencyLimiter(c.Max, c.Queue)
+		if c.Adaptive.Enabled {
+			m.global.adaptive = newAIMD(c.Adaptive, c.Max)
+		}
+	}
+	for _, r := range c.Routes {
+		if r.Max > 0 {
+			m.routes[routeKey(r.Method, r.Path)] = newConcurrencyLimiter(r.Max, r.Queue)
+		}
+	}
+	return m
+}
+
+func (m *ConcurrencyMiddleware) Handler() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		limiters := make([]*concurrencyLimiter, 0, 2)
+		if m.global != nil {
+			limiters = append(limiters, m.global)
+		}
+		if l := m.match(ctx); l != nil {
+			limiters = append(limiters, l)
+		}
+
+		for i, l := range limiters {
+			if !l.Acquire(ctx, m.queueTimeout) {
+				for _, acquired := range limiters[:i] {
+					acquired.Release(0)
+				}
+				m.shed(ctx, l)
+				return
+			}
+		}
+
+		start := time.Now()
+		defer func() {
+			latency := time.Since(start)
+			for _, l := range limiters {
+				l.Release(latency)
+			}
+		}()
+		ctx.Next()
+	}
+}
+
+func (m *ConcurrencyMiddleware) match(ctx *gin.Context) *concurrencyLimiter {
+	path := ctx.FullPath()
+	if l, ok := m.routes[routeKey(ctx.Request.Method, path)]; ok {
+		return l
+	}
+	return m.routes[routeKey("", path)]
+}
+
+// shed 超出并发上限或排队超时，返回 503
+func (m *ConcurrencyMiddleware) shed(ctx *gin.Context, l *concurrencyLimiter) {
+	limit, inflight := l.Stats()
+	m.log.Warn(ctx, "并发超限，请求被拒绝",
+		logger.StringAny("url", ctx.Request.URL.Path),
+		logger.StringAny("limit", limit),
+		logger.StringAny("inflight", inflight),
+	)
+	ctx.Header("Retry-After", "1")
+	response.Error(ctx, errs.Unavailable)
+}
+
+// concurrencyLimiter 带有限等待队列的信号量，上限可由 aimd 动态调整
+type concurrencyLimiter struct {
+	mu       sync.Mutex
+	limit    int
+	inflight int
+	queue    int
+	waiters  list.List
+	adaptive *aimd
+}
+
+func newConcurrencyLimiter(limit, queue int) *concurrencyLimiter {
+	return &concurrencyLimiter{limit: limit, queue: max(queue, 0)}
+}
+
+// Acquire 获取并发名额，队列已满、排队超时或请求取消时返回 false
+func (l *concurrencyLimiter) Acquire(ctx context.Context, timeout time.Duration) bool {
+	l.mu.Lock()
+	if l.inflight < l.limit && l.waiters.Len() == 0 {
+		l.inflight++
+		l.mu.Unlock()
+		return true
+	}
+	if l.waiters.Len() >= l.queue {
+		l.mu.Unlock()
+		return false
+	}
+	ready := make(chan struct{})
+	elem := l.waiters.PushBack(ready)
+	l.mu.Unlock()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-ready:
+		return true
+	case <-timer.C:
+	case <-ctx.Done():
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	select {
+	case <-ready:
+		// 超时的同时已获得名额
+		return true
+	default:
+		l.waiters.Remove(elem)
+		return false
+	}
+}
+
+// Release 归还名额，latency>0 时参与自适应调整
+func (l *concurrencyLimiter) Release(latency time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.inflight--
+	if l.adaptive != nil && latency > 0 {
+		l.limit = l.adaptive.Observe(latency)
+	}
+	for l.inflight < l.limit && l.waiters.Len() > 0 {
+		ready := l.waiters.Remove(l.waiters.Front()).(chan struct{})
+		l.inflight++
+		close(ready)
+	}
+}
+
+func (l *concurrencyLimiter) Stats() (limit, inflight int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.limit, l.inflight
+}
+
+// aimd 延迟低于目标时加性增长，超过目标时乘性减小，避免延迟雪崩
+type aimd struct {
+	limit    float64
+	min      float64
+	max      float64
+	target   time.Duration
+	backoff  float64
+	lastDrop time.Time
+}
+
+func newAIMD(cfg config.AdaptiveConfig, maxLimit int) *aimd {
+	a := &aimd{
+		limit:   float64(maxLimit),
+		min:     float64(max(cfg.MinLimit, 1)),
+		max:     float64(maxLimit),
+		target:  cfg.TargetLatency,
+		backoff: cfg.Backoff,
+	}
+	if a.target <= 0 {
+		a.target = 100 * time.Millisecond
+	}
+	if a.backoff <= 0 || a.backoff >= 1 {
+		a.backoff = 0.9
+	}
+	return a
+}
+
+// Observe 记录一次请求延迟并返回新的并发上限，调用方需持有锁
+func (a *aimd) Observe(latency time.Duration) int {
+	now := time.Now()
+	if latency > a.target {
+		// 同一目标延迟窗口内只减小一次
+		if now.Sub(a.lastDrop) >= a.target {
+			a.limit = math.Max(a.min, a.limit*a.backoff)
+			a.lastDrop = now
+		}
+	} else {
+		a.limit = math.Min(a.max, a.limit+1/a.limit)
+	}
+	return int(a.limit)
+}
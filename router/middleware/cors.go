@@ -1,63 +1,194 @@
 package middleware
 
 import (
+	"fmt"
+	"go-wire/auth"
 	"go-wire/config"
 	"go-wire/logger"
 	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 )
 
+var (
+	defaultCorsMethods = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"}
+	defaultCorsHeaders = []string{
+		"Content-Type", "Authorization", auth.APIKeyHeader,
+		auth.HMACClientHeader, auth.HMACTimestampHeader, auth.HMACNonceHeader, auth.HMACSignatureHeader,
+		defaultIdempotencyHeader,
+	}
+)
+
 type CorsMiddleware struct {
-	cfg *config.Config
-	log logger.Logger
+	log       logger.Logger
+	anyOrigin bool
+	exact     map[string]struct{}
+	wildcard  [][2]string // 通配来源拆分后的前缀与后缀
+	regex     []*regexp.Regexp
+	policy    corsPolicy
+	groups    []corsGroup
 }
 
-func NewCorsMiddleware(cfg *config.Config, log logger.Logger) *CorsMiddleware {
-	return &CorsMiddleware{cfg: cfg, log: log}
+// corsPolicy 预先拼接好的跨域响应头
+type corsPolicy struct {
+	methods     string
+	headers     string
+	expose      string
+	credentials bool
+	maxAge      string
+}
+
+type corsGroup struct {
+	prefix string
+	policy corsPolicy
+}
+
+func NewCorsMiddleware(cfg *config.Config, log logger.Logger) (*CorsMiddleware, error) {
+	c := cfg.Cors
+	m := &CorsMiddleware{
+		log:   log,
+		exact: make(map[string]struct{}, len(c.AllowOrigins)),
+	}
+	for _, origin := range c.AllowOrigins {
+		switch {
+		case origin == "*":
+			m.anyOrigin = true
+		case strings.Count(origin, "*") == 1:
+			prefix, suffix, _ := strings.Cut(origin, "*")
+			m.wildcard = append(m.wildcard, [2]string{prefix, suffix})
+		default:
+			m.exact[origin] = struct{}{}
+		}
+	}
+	for _, expr := range c.AllowOriginRegex {
+		// 整体锚定，避免 example\.com 匹配 example.com.evil.io
+		re, err := regexp.Compile("^(?:" + expr + ")$")
+		if err != nil {
+			return nil, fmt.Errorf("跨域来源正则 [%s] 解析失败: %w", expr, err)
+		}
+		m.regex = append(m.regex, re)
+	}
+
+	m.policy = corsPolicy{
+		methods:     strings.Join(orDefault(c.AllowMethods, defaultCorsMethods), ","),
+		headers:     strings.Join(orDefault(c.AllowHeaders, defaultCorsHeaders), ","),
+		expose:      strings.Join(c.ExposeHeaders, ","),
+		credentials: c.AllowCredentials,
+		maxAge:      strconv.Itoa(int(c.MaxAge.Seconds())),
+	}
+	for _, g := range c.Groups {
+		p := m.policy
+		if len(g.AllowMethods) > 0 {
+			p.methods = strings.Join(g.AllowMethods, ",")
+		}
+		if len(g.AllowHeaders) > 0 {
+			p.headers = strings.Join(g.AllowHeaders, ",")
+		}
+		if len(g.ExposeHeaders) > 0 {
+			p.expose = strings.Join(g.ExposeHeaders, ",")
+		}
+		if g.AllowCredentials != nil {
+			p.credentials = *g.AllowCredentials
+		}
+		if g.MaxAge > 0 {
+			p.maxAge = strconv.Itoa(int(g.MaxAge.Seconds()))
+		}
+		m.groups = append(m.groups, corsGroup{prefix: g.Prefix, policy: p})
+	}
+	sort.SliceStable(m.groups, func(i, j int) bool {
+		return len(m.groups[i].prefix) > len(m.groups[j].prefix)
+	})
+	return m, nil
 }
 
 func (m *CorsMiddleware) Handler() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
-		origin := ctx.GetHeader("Origin")
-
-		// 设置跨域响应头
-		setHeaders(ctx, origin)
+		// 响应随 Origin 变化，缓存需区分
+		ctx.Writer.Header().Add("Vary", "Origin")
 
-		// OPTIONS 方法直接返回
-		if ctx.Request.Method == http.MethodOptions {
-			ctx.AbortWithStatus(http.StatusNoContent)
+		origin := ctx.GetHeader("Origin")
+		// 非跨域请求
+		if origin == "" {
+			ctx.Next()
 			return
 		}
 
-		// 调试模式下放行所有请求
-		if m.cfg.App.Mode == "debug" {
+		// 来源不在允许列表时不返回跨域头，由浏览器拦截
+		if !m.allowed(origin) {
+			m.log.Debug(ctx, "跨域来源不允许", logger.StringAny("origin", origin))
 			ctx.Next()
 			return
 		}
 
-		// 校验跨域请求
-		if _, ok := m.cfg.AllowedOriginsMap[origin]; !ok {
-			// 拒绝跨域
-			m.log.Warn(ctx, "跨域", logger.StringAny("origin", origin))
-			ctx.AbortWithStatusJSON(http.StatusForbidden, gin.H{
-				"code": http.StatusForbidden,
-				"msg":  "跨域来源不允许",
-			})
+		policy := m.match(ctx.Request.URL.Path)
+		if m.anyOrigin && !policy.credentials {
+			ctx.Header("Access-Control-Allow-Origin", "*")
+		} else {
+			ctx.Header("Access-Control-Allow-Origin", origin)
+		}
+		if policy.credentials {
+			ctx.Header("Access-Control-Allow-Credentials", "true")
+		}
+
+		// 预检请求直接返回
+		if ctx.Request.Method == http.MethodOptions && ctx.GetHeader("Access-Control-Request-Method") != "" {
+			ctx.Writer.Header().Add("Vary", "Access-Control-Request-Method")
+			ctx.Writer.Header().Add("Vary", "Access-Control-Request-Headers")
+			ctx.Header("Access-Control-Allow-Methods", policy.methods)
+			ctx.Header("Access-Control-Allow-Headers", policy.headers)
+			ctx.Header("Access-Control-Max-Age", policy.maxAge)
+			ctx.AbortWithStatus(http.StatusNoContent)
 			return
 		}
 
-		// 处理请求
+		if policy.expose != "" {
+			ctx.Header("Access-Control-Expose-Headers", policy.expose)
+		}
 		ctx.Next()
 	}
 }
 
-// setHeaders 设置允许跨域请求的响应头
-func setHeaders(ctx *gin.Context, origin string) {
-	ctx.Header("Access-Control-Allow-Origin", origin)
-	ctx.Header("Access-Control-Allow-Methods", "POST,GET,OPTIONS,DELETE,PUT")
-	ctx.Header("Access-Control-Allow-Headers", "Content-Type,AccessToken,X-CSRF-Token,Authorization,Token,X-Token,X-User-Id")
-	ctx.Header("Access-Control-Expose-Headers", "Content-Length,Access-Control-Allow-Origin,Access-Control-Allow-Headers,Content-Type,New-Token,New-Expires-At")
-	ctx.Header("Access-Control-Allow-Credentials", "true")
-	ctx.Header("Access-Control-Max-Age", "86400")
+// allowed 依次按精确、子域名通配、正则匹配来源
+func (m *CorsMiddleware) allowed(origin string) bool {
+	if m.anyOrigin {
+		return true
+	}
+	if _, ok := m.exact[origin]; ok {
+		return true
+	}
+	for _, w := range m.wildcard {
+		if len(origin) > len(w[0])+len(w[1]) && strings.HasPrefix(origin, w[0]) && strings.HasSuffix(origin, w[1]) {
+			// 通配部分不允许跨越路径或携带凭证
+			if !strings.ContainsAny(origin[len(w[0]):len(origin)-len(w[1])], "/@") {
+				return true
+			}
+		}
+	}
+	for _, re := range m.regex {
+		if re.MatchString(origin) {
+			return true
+		}
+	}
+	return false
+}
+
+// match 按路由前缀最长匹配组策略
+func (m *CorsMiddleware) match(path string) corsPolicy {
+	for _, g := range m.groups {
+		if strings.HasPrefix(path, g.prefix) {
+			return g.policy
+		}
+	}
+	return m.policy
+}
+
+func orDefault(values, def []string) []string {
+	if len(values) > 0 {
+		return values
+	}
+	return def
 }
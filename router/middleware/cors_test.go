@@ -0,0 +1,76 @@
+package middleware
+
+import (
+	"go-wire/config"
+	"go-wire/logger"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func newCorsEngine(t *testing.T, c config.CorsConfig) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	m, err := NewCorsMiddleware(&config.Config{Cors: c}, logger.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+	engine := gin.New()
+	engine.Use(m.Handler())
+	engine.GET("/api/test", func(ctx *gin.Context) { ctx.Status(http.StatusOK) })
+	return engine
+}
+
+func TestCorsOrigins(t *testing.T) {
+	engine := newCorsEngine(t, config.CorsConfig{
+		AllowOrigins:     []string{"https://app.example.com", "https://*.example.org"},
+		AllowOriginRegex: []string{`https://dev\d+\.example\.net`},
+	})
+
+	cases := []struct {
+		origin  string
+		allowed bool
+	}{
+		{"https://app.example.com", true},
+		{"https://a.example.org", true},
+		{"https://a.b@example.org", false},
+		{"https://dev1.example.net", true},
+		// 正则整体锚定
+		{"https://dev1.example.net.evil.io", false},
+		{"https://evil.io/https://dev1.example.net", false},
+		{"https://evil.io", false},
+	}
+	for _, c := range cases {
+		w := serve(engine, "/api/test", "10.0.0.1:1234", "Origin", c.origin)
+		// 不允许的来源不拦截请求，仅不返回跨域头
+		if w.Code != http.StatusOK {
+			t.Errorf("%s: status=%d", c.origin, w.Code)
+		}
+		if got := w.Header().Get("Access-Control-Allow-Origin"); (got == c.origin) != c.allowed {
+			t.Errorf("%s: Access-Control-Allow-Origin=%q, allowed=%v", c.origin, got, c.allowed)
+		}
+	}
+}
+
+func TestCorsPreflightDefaultHeaders(t *testing.T) {
+	engine := newCorsEngine(t, config.CorsConfig{AllowOrigins: []string{"*"}})
+
+	req := httptest.NewRequest(http.MethodOptions, "/api/test", nil)
+	req.Header.Set("Origin", "https://app.example.com")
+	req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("预检请求: status=%d", w.Code)
+	}
+	headers := w.Header().Get("Access-Control-Allow-Headers")
+	for _, h := range []string{"X-Api-Key", "X-Client-Id", "X-Timestamp", "X-Nonce", "X-Signature", "Idempotency-Key"} {
+		if !strings.Contains(headers, h) {
+			t.Errorf("默认允许的请求头缺少 %s: %s", h, headers)
+		}
+	}
+}
@@ -3,10 +3,10 @@ package middleware
 import (
 	"fmt"
 	"go-wire/config"
-	"go-wire/constant"
+	"go-wire/errs"
 	"go-wire/logger"
+	"go-wire/response"
 	"net"
-	"net/http"
 	"net/http/httputil"
 	"os"
 	"runtime/debug"
@@ -36,14 +36,6 @@ func (m *ErrorMiddleware) Handler() gin.HandlerFunc {
 }
 
 func (m *ErrorMiddleware) handlePanic(ctx *gin.Context, tag string, r any) {
-	if be, ok := r.(constant.ErrorResponse); ok {
-		ctx.AbortWithStatusJSON(http.StatusOK, gin.H{
-			"code": be.Code,
-			"msg":  be.Msg,
-		})
-		return
-	}
-
 	var brokenPipe bool
 
 	if ne, ok := r.(*net.OpError); ok {
@@ -73,7 +65,6 @@ func (m *ErrorMiddleware) handlePanic(ctx *gin.Context, tag string, r any) {
 		return
 	}
 
-	// 是否是业务 error，可以加一个类型断言判断业务 error（可选）
 	m.log.Error(ctx, "服务异常捕获",
 		logger.StringAny("type", "panic"),
 		logger.StringAny("error", errMsg),
@@ -81,10 +72,7 @@ func (m *ErrorMiddleware) handlePanic(ctx *gin.Context, tag string, r any) {
 		logger.StringAny("stack", stack),
 	)
 
-	ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
-		"code": constant.ERROR,
-		"msg":  "服务器开小差，请稍后再试",
-	})
+	response.Error(ctx, errs.Internal.Wrap(fmt.Errorf("panic: %s", errMsg)))
 }
 
 func (m *ErrorMiddleware) formatStackTrace(tag string) []string {
@@ -0,0 +1,320 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go-wire/config"
+	"go-wire/errs"
+	"go-wire/logger"
+	"go-wire/redis"
+	"go-wire/reqctx"
+	"go-wire/response"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	goredis "github.com/redis/go-redis/v9"
+)
+
+const (
+	idempotencyKeyPrefix      = "idempotency:"
+	defaultIdempotencyHeader  = "Idempotency-Key"
+	defaultIdempotencyTTL     = 24 * time.Hour
+	defaultIdempotencyLockTTL = time.Minute
+	idempotencyPollInterval   = 100 * time.Millisecond
+
+	idempotencyProcessing = "processing"
+	idempotencyDone       = "done"
+)
+
+// idempotencyRecord 幂等键对应的请求指纹与处理结果
+type idempotencyRecord struct {
+	State       string      `json:"state"`
+	Fingerprint string      `json:"fingerprint"`
+	Status      int         `json:"status,omitempty"`
+	Header      http.Header `json:"header,omitempty"`
+	Body        []byte      `json:"body,omitempty"`
+}
+
+type IdempotencyMiddleware struct {
+	log     logger.Logger
+	client  goredis.UniversalClient
+	header  string
+	ttl     time.Duration
+	lockTTL time.Duration
+	wait    time.Duration
+	routes  map[string]bool // 路由 -> 是否必须携带幂等键
+}
+
+func NewIdempotencyMiddleware(cfg *config.Config, log logger.Logger, rdb *redis.Redis) (*IdempotencyMiddleware, error) {
+	c := cfg.Idempotency
+	m := &IdempotencyMiddleware{
+		log:     log,
+		header:  c.Header,
+		ttl:     c.TTL,
+		lockTTL: c.LockTTL,
+		wait:    c.Wait,
+		routes:  make(map[string]bool, len(c.Routes)),
+	}
+	if m.header == "" {
+		m.header = defaultIdempotencyHeader
+	}
+	if m.ttl <= 0 {
+		m.ttl = defaultIdempotencyTTL
+	}
+	if m.lockTTL <= 0 {
+		m.lockTTL = defaultIdempotencyLockTTL
+	}
+	for _, r := range c.Routes {
+		m.routes[routeKey(r.Method, r.Path)] = r.Required
+	}
+	if len(m.routes) == 0 {
+		return m, nil
+	}
+
+	name := c.Redis
+	if name == "" {
+		name = "default"
+	}
+	client, err := rdb.Client(name)
+	if err != nil {
+		return nil, fmt.Errorf("幂等中间件初始化失败: %w", err)
+	}
+	m.client = client
+	return m, nil
+}
+
+// Handler 按幂等键保存首次请求的响应，重复请求直接返回保存的响应
+// 幂等键按请求主体隔离；相同幂等键携带不同请求内容返回 422，首个请求仍在处理时等待或返回 409
+func (m *IdempotencyMiddleware) Handler() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		required, ok := m.match(ctx)
+		if !ok {
+			ctx.Next()
+			return
+		}
+		key := strings.TrimSpace(ctx.GetHeader(m.header))
+		if key == "" {
+			if required {
+				response.Error(ctx, errs.IdempotencyMissing)
+				return
+			}
+			ctx.Next()
+			return
+		}
+		fingerprint, err := m.fingerprint(ctx)
+		if err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				response.Error(ctx, errs.PayloadTooLarge.Wrap(err))
+				return
+			}
+			response.Error(ctx, errs.BadRequest.Wrap(err))
+			return
+		}
+		m.serve(ctx, m.storageKey(ctx, key), fingerprint)
+	}
+}
+
+// match 仅非安全方法且配置了幂等的路由生效
+func (m *IdempotencyMiddleware) match(ctx *gin.Context) (required bool, ok bool) {
+	switch ctx.Request.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+		return false, false
+	}
+	path := ctx.FullPath()
+	if required, ok = m.routes[routeKey(ctx.Request.Method, path)]; ok {
+		return required, ok
+	}
+	required, ok = m.routes[routeKey("", path)]
+	return required, ok
+}
+
+// fingerprint 请求方法、地址与请求体的摘要，读取后恢复请求体供后续绑定
+func (m *IdempotencyMiddleware) fingerprint(ctx *gin.Context) (string, error) {
+	var body []byte
+	if ctx.Request.Body != nil && ctx.Request.Body != http.NoBody {
+		var err error
+		if body, err = io.ReadAll(ctx.Request.Body); err != nil {
+			return "", err
+		}
+		ctx.Request.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	h := sha256.New()
+	h.Write([]byte(ctx.Request.Method + "\n" + ctx.Request.URL.RequestURI() + "\n"))
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// storageKey 幂等键按请求主体隔离，避免不同用户的相同幂等键互相命中
+func (m *IdempotencyMiddleware) storageKey(ctx *gin.Context, key string) string {
+	owner := ""
+	if p, ok := reqctx.PrincipalFrom(ctx.Request.Context()); ok && !p.Anonymous() {
+		owner = p.Type + ":" + p.ID
+	}
+	sum := sha256.Sum256([]byte(owner + "\n" + key))
+	return idempotencyKeyPrefix + hex.EncodeToString(sum[:])
+}
+
+func (m *IdempotencyMiddleware) serve(ctx *gin.Context, key, fingerprint string) {
+	reqCtx := ctx.Request.Context()
+	deadline := time.Now().Add(m.wait)
+	for {
+		acquired, rec, err := m.acquire(reqCtx, key, fingerprint)
+		if err != nil {
+			// redis 不可用时不做幂等控制，与未携带幂等键的请求一致
+			m.log.Warn(ctx, "幂等记录读写失败，跳过幂等控制", logger.Error(err))
+			ctx.Next()
+			return
+		}
+		if acquired {
+			m.process(ctx, key, fingerprint)
+			return
+		}
+		if rec == nil {
+			// 记录在抢占与读取之间过期，重新抢占
+			continue
+		}
+		if rec.Fingerprint != fingerprint {
+			response.Error(ctx, errs.IdempotencyReused)
+			return
+		}
+		if rec.State == idempotencyDone {
+			m.replay(ctx, rec)
+			return
+		}
+		if !time.Now().Before(deadline) {
+			ctx.Header("Retry-After", "1")
+			response.Error(ctx, errs.IdempotencyBusy)
+			return
+		}
+		select {
+		case <-reqCtx.Done():
+			if errors.Is(reqCtx.Err(), context.Canceled) {
+				// 客户端已断开，不再写出响应
+				ctx.Abort()
+				return
+			}
+			response.Error(ctx, reqCtx.Err())
+			return
+		case <-time.After(idempotencyPollInterval):
+		}
+	}
+}
+
+// acquire 写入处理中标记，已存在时返回现有记录，记录已过期时返回 nil
+func (m *IdempotencyMiddleware) acquire(ctx context.Context, key, fingerprint string) (bool, *idempotencyRecord, error) {
+	data, err := json.Marshal(idempotencyRecord{State: idempotencyProcessing, Fingerprint: fingerprint})
+	if err != nil {
+		return false, nil, err
+	}
+	ok, err := m.client.SetNX(ctx, key, data, m.lockTTL).Result()
+	if err != nil || ok {
+		return ok, nil, err
+	}
+	raw, err := m.client.Get(ctx, key).Bytes()
+	if errors.Is(err, goredis.Nil) {
+		return false, nil, nil
+	}
+	if err != nil {
+		return false, nil, err
+	}
+	var rec idempotencyRecord
+	if err := json.Unmarshal(raw, &rec); err != nil {
+		return false, nil, err
+	}
+	return false, &rec, nil
+}
+
+// process 执行请求并保存响应，服务端错误不保存以便客户端重试
+func (m *IdempotencyMiddleware) process(ctx *gin.Context, key, fingerprint string) {
+	// 请求结束或超时后仍需写入结果
+	storeCtx := reqctx.Detach(ctx.Request.Context())
+	before := ctx.Writer.Header().Clone()
+	w := &recordWriter{ResponseWriter: ctx.Writer}
+	ctx.Writer = w
+	completed := false
+	defer func() {
+		ctx.Writer = w.ResponseWriter
+		if !completed {
+			m.client.Del(storeCtx, key)
+		}
+	}()
+	ctx.Next()
+
+	status := w.Status()
+	if status >= http.StatusInternalServerError {
+		return
+	}
+	rec := idempotencyRecord{
+		State:       idempotencyDone,
+		Fingerprint: fingerprint,
+		Status:      status,
+		Header:      changedHeader(before, w.Header()),
+		Body:        w.body.Bytes(),
+	}
+	data, err := json.Marshal(rec)
+	if err == nil {
+		err = m.client.Set(storeCtx, key, data, m.ttl).Err()
+	}
+	if err != nil {
+		m.log.Warn(ctx, "幂等响应保存失败", logger.Error(err))
+		return
+	}
+	completed = true
+}
+
+// replay 返回保存的响应，已由前置中间件设置的响应头不重复添加
+func (m *IdempotencyMiddleware) replay(ctx *gin.Context, rec *idempotencyRecord) {
+	h := ctx.Writer.Header()
+	for k, values := range rec.Header {
+		for _, v := range values {
+			if !slices.Contains(h.Values(k), v) {
+				h.Add(k, v)
+			}
+		}
+	}
+	h.Set("Idempotent-Replayed", "true")
+	ctx.Writer.WriteHeader(rec.Status)
+	if len(rec.Body) > 0 {
+		_, _ = ctx.Writer.Write(rec.Body)
+	} else {
+		ctx.Writer.WriteHeaderNow()
+	}
+	ctx.Abort()
+}
+
+// changedHeader 处理过程中新增或修改的响应头
+func changedHeader(before, after http.Header) http.Header {
+	res := make(http.Header)
+	for k, v := range after {
+		if !slices.Equal(before[k], v) {
+			res[k] = v
+		}
+	}
+	return res
+}
+
+// recordWriter 写出响应的同时记录响应体
+type recordWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *recordWriter) Write(p []byte) (int, error) {
+	w.body.Write(p)
+	return w.ResponseWriter.Write(p)
+}
+
+func (w *recordWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
@@ -0,0 +1,140 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"go-wire/config"
+	"go-wire/logger"
+	"go-wire/redis"
+	"go-wire/reqctx"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	goredis "github.com/redis/go-redis/v9"
+)
+
+type idempotencyEngine struct {
+	*gin.Engine
+	mr    *miniredis.Miniredis
+	calls int
+}
+
+func newIdempotencyEngine(t *testing.T, wait time.Duration) *idempotencyEngine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	mr := miniredis.RunT(t)
+	client := goredis.NewClient(&goredis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { client.Close() })
+
+	cfg := &config.Config{}
+	cfg.Idempotency.Wait = wait
+	cfg.Idempotency.Routes = []config.IdempotencyRoute{{Path: "/orders", Required: true}}
+	m, err := NewIdempotencyMiddleware(cfg, logger.NewNop(), &redis.Redis{Clients: map[string]goredis.UniversalClient{"default": client}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	e := &idempotencyEngine{Engine: gin.New(), mr: mr}
+	e.Use(func(ctx *gin.Context) {
+		if id := ctx.GetHeader("X-User"); id != "" {
+			ctx.Request = ctx.Request.WithContext(reqctx.WithPrincipal(ctx.Request.Context(), &reqctx.Principal{ID: id, Type: "jwt"}))
+		}
+	}, m.Handler())
+	e.POST("/orders", func(ctx *gin.Context) {
+		e.calls++
+		body, _ := io.ReadAll(ctx.Request.Body)
+		ctx.String(http.StatusCreated, "order:%s:%d", body, e.calls)
+	})
+	return e
+}
+
+func (e *idempotencyEngine) post(ctx context.Context, key, user, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequestWithContext(ctx, http.MethodPost, "/orders", strings.NewReader(body))
+	if key != "" {
+		req.Header.Set(defaultIdempotencyHeader, key)
+	}
+	if user != "" {
+		req.Header.Set("X-User", user)
+	}
+	w := httptest.NewRecorder()
+	e.ServeHTTP(w, req)
+	return w
+}
+
+func TestIdempotencyReplay(t *testing.T) {
+	e := newIdempotencyEngine(t, 0)
+	ctx := context.Background()
+
+	first := e.post(ctx, "k1", "u1", "a")
+	if first.Code != http.StatusCreated || first.Body.String() != "order:a:1" {
+		t.Fatalf("首次请求: status=%d body=%s", first.Code, first.Body)
+	}
+	second := e.post(ctx, "k1", "u1", "a")
+	if second.Code != http.StatusCreated || second.Body.String() != first.Body.String() {
+		t.Fatalf("重复请求应返回保存的响应: status=%d body=%s", second.Code, second.Body)
+	}
+	if second.Header().Get("Idempotent-Replayed") != "true" || e.calls != 1 {
+		t.Fatalf("重复请求不应再次执行: replayed=%q calls=%d", second.Header().Get("Idempotent-Replayed"), e.calls)
+	}
+
+	if w := e.post(ctx, "k1", "u1", "b"); w.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("相同幂等键不同请求体: status=%d, want 422", w.Code)
+	}
+	// 幂等键按请求主体隔离
+	if w := e.post(ctx, "k1", "u2", "a"); w.Code != http.StatusCreated || e.calls != 2 {
+		t.Fatalf("其他主体的相同幂等键: status=%d calls=%d", w.Code, e.calls)
+	}
+	if w := e.post(ctx, "", "u1", "a"); w.Code != http.StatusBadRequest {
+		t.Fatalf("缺少幂等键: status=%d, want 400", w.Code)
+	}
+}
+
+func TestIdempotencyBusy(t *testing.T) {
+	e := newIdempotencyEngine(t, 0)
+	e.post(context.Background(), "k1", "u1", "a")
+	markProcessing(t, e)
+	if w := e.post(context.Background(), "k1", "u1", "a"); w.Code != http.StatusConflict || w.Header().Get("Retry-After") == "" {
+		t.Fatalf("处理中的重复请求: status=%d retry=%q, want 409", w.Code, w.Header().Get("Retry-After"))
+	}
+}
+
+// 等待首个请求完成时客户端断开，不写出错误响应
+func TestIdempotencyClientCanceled(t *testing.T) {
+	e := newIdempotencyEngine(t, time.Minute)
+	e.post(context.Background(), "k1", "u1", "a")
+	markProcessing(t, e)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+	w := e.post(ctx, "k1", "u1", "a")
+	if w.Body.Len() != 0 || e.calls != 1 {
+		t.Fatalf("客户端断开后不应写出响应: status=%d body=%s calls=%d", w.Code, w.Body, e.calls)
+	}
+}
+
+// markProcessing 将已保存的响应改回处理中，模拟首个请求尚未完成
+func markProcessing(t *testing.T, e *idempotencyEngine) {
+	t.Helper()
+	keys := e.mr.Keys()
+	if len(keys) != 1 {
+		t.Fatalf("幂等记录: %v", keys)
+	}
+	raw, err := e.mr.Get(keys[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	var rec idempotencyRecord
+	if err = json.Unmarshal([]byte(raw), &rec); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := json.Marshal(idempotencyRecord{State: idempotencyProcessing, Fingerprint: rec.Fingerprint})
+	if err = e.mr.Set(keys[0], string(data)); err != nil {
+		t.Fatal(err)
+	}
+}
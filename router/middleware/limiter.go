@@ -1,44 +1,186 @@
 package middleware
 
 import (
+	"context"
+	"fmt"
 	"go-wire/config"
+	"go-wire/errs"
 	"go-wire/logger"
-	"net/http"
+	"go-wire/redis"
+	"go-wire/reqctx"
+	"go-wire/response"
+	"math"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
-	"golang.org/x/time/rate"
 )
 
+// KeyFunc 从请求中提取限流维度
+type KeyFunc func(ctx *gin.Context) string
+
+// LimitPolicy 限流策略
+type LimitPolicy struct {
+	Key   string  // 限流维度
+	Limit float64 // 每秒令牌数
+	Burst int     // 令牌桶容量
+}
+
+// LimitResult 限流结果
+type LimitResult struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	RetryAfter time.Duration
+}
+
+// rateLimiter 限流器，进程内与 redis 实现共用同一套 key 与策略
+type rateLimiter interface {
+	Allow(ctx context.Context, key string, policy LimitPolicy) (LimitResult, error)
+}
+
 type LimiterMiddleware struct {
-	log     logger.Logger
-	limiter *rate.Limiter
+	log      logger.Logger
+	limiter  rateLimiter
+	keyFuncs map[string]KeyFunc
+	policy   LimitPolicy
+	routes   map[string]LimitPolicy
 }
 
-func NewLimiterMiddleware(log logger.Logger, limiter *rate.Limiter) *LimiterMiddleware {
+func NewLimiterMiddleware(cfg *config.Config, log logger.Logger, rdb *redis.Redis) (*LimiterMiddleware, error) {
+	policy := LimitPolicy{
+		Key:   cfg.Limiter.Key,
+		Limit: cfg.App.Limit,
+		Burst: cfg.App.Burst,
+	}
+	if policy.Key == "" {
+		policy.Key = "ip"
+	}
+
+	routes := make(map[string]LimitPolicy, len(cfg.Limiter.Routes))
+	for _, r := range cfg.Limiter.Routes {
+		key := r.Key
+		if key == "" {
+			key = policy.Key
+		}
+		routes[routeKey(r.Method, r.Path)] = LimitPolicy{Key: key, Limit: r.Limit, Burst: r.Burst}
+	}
+
+	var limiter rateLimiter = newLocalLimiter(cfg.Limiter.IdleTimeout)
+	if cfg.Limiter.Driver == "redis" {
+		client, err := rdb.Client(cfg.Limiter.Redis)
+		if err != nil {
+			return nil, fmt.Errorf("限流器初始化失败: %w", err)
+		}
+		limiter = &redisLimiter{
+			client:   client,
+			local:    limiter.(*localLimiter),
+			fallback: cfg.Limiter.Fallback,
+			log:      log,
+		}
+	}
+
 	return &LimiterMiddleware{
 		log:     log,
 		limiter: limiter,
-	}
+		keyFuncs: map[string]KeyFunc{
+			"ip":     clientIPKey,
+			"client": clientIDKey,
+			"user":   userKey,
+		},
+		policy: policy,
+		routes: routes,
+	}, nil
+}
+
+// RegisterKeyFunc 注册自定义限流维度，需在服务启动前调用
+func (m *LimiterMiddleware) RegisterKeyFunc(name string, fn KeyFunc) {
+	m.keyFuncs[name] = fn
 }
 
 func (m *LimiterMiddleware) Handler() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
+		policy, scope := m.match(ctx)
+		if policy.Limit <= 0 {
+			ctx.Next()
+			return
+		}
+
+		key := scope + "|" + policy.Key + ":" + m.key(ctx, policy.Key)
+		res, err := m.limiter.Allow(ctx, key, policy)
+		if err != nil {
+			m.log.Error(ctx, "限流检查失败", logger.Error(err))
+			ctx.Next()
+			return
+		}
+		setRateLimitHeaders(ctx, res)
+
 		// 检查请求是否被限流
-		if !m.limiter.Allow() {
+		if !res.Allowed {
 			m.log.Warn(ctx, "请求被限流",
 				logger.StringAny("url", ctx.Request.URL.Path),
-				logger.StringAny("client_ip", ctx.ClientIP()),
+				logger.StringAny("key", key),
 			)
-			ctx.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
-				"code": http.StatusTooManyRequests,
-				"msg":  "服务繁忙，请稍后再试...",
-			})
+			response.Error(ctx, errs.TooManyRequests)
 			return
 		}
 		ctx.Next()
 	}
 }
 
-func NewLimiter(cfg *config.Config) *rate.Limiter {
-	return rate.NewLimiter(rate.Limit(cfg.App.Limit), cfg.App.Burst)
+// match 查找路由策略，未配置时使用全局策略
+func (m *LimiterMiddleware) match(ctx *gin.Context) (LimitPolicy, string) {
+	path := ctx.FullPath()
+	for _, k := range []string{routeKey(ctx.Request.Method, path), routeKey("", path)} {
+		if p, ok := m.routes[k]; ok {
+			return p, k
+		}
+	}
+	return m.policy, "*"
+}
+
+func (m *LimiterMiddleware) key(ctx *gin.Context, name string) string {
+	if fn, ok := m.keyFuncs[name]; ok {
+		if key := fn(ctx); key != "" {
+			return key
+		}
+	}
+	return ctx.ClientIP()
+}
+
+func routeKey(method, path string) string {
+	method = strings.ToUpper(method)
+	if method == "" {
+		method = "*"
+	}
+	return method + " " + path
+}
+
+func clientIPKey(ctx *gin.Context) string {
+	return ctx.ClientIP()
+}
+
+// clientIDKey 认证通过的调用方，按认证方式与主体标识区分，未认证时回退到客户端 IP
+func clientIDKey(ctx *gin.Context) string {
+	if p, ok := reqctx.PrincipalFrom(ctx.Request.Context()); ok && !p.Anonymous() {
+		return p.Type + ":" + p.ID
+	}
+	return ""
+}
+
+func userKey(ctx *gin.Context) string {
+	if p, ok := reqctx.PrincipalFrom(ctx.Request.Context()); ok && !p.Anonymous() {
+		return p.ID
+	}
+	return ""
+}
+
+// setRateLimitHeaders 写入 RateLimit-* 响应头
+func setRateLimitHeaders(ctx *gin.Context, res LimitResult) {
+	ctx.Header("RateLimit-Limit", strconv.Itoa(res.Limit))
+	ctx.Header("RateLimit-Remaining", strconv.Itoa(res.Remaining))
+	if !res.Allowed {
+		ctx.Header("Retry-After", strconv.Itoa(int(math.Ceil(res.RetryAfter.Seconds()))))
+	}
 }
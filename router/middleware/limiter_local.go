@@ -0,0 +1,76 @@
+package middleware
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+const defaultIdleTimeout = 10 * time.Minute
+
+// localLimiter 进程内按 key 分桶的令牌桶，空闲桶定期回收
+type localLimiter struct {
+	mu        sync.Mutex
+	entries   map[string]*limiterEntry
+	idle      time.Duration
+	lastSweep time.Time
+}
+
+type limiterEntry struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+func newLocalLimiter(idle time.Duration) *localLimiter {
+	if idle <= 0 {
+		idle = defaultIdleTimeout
+	}
+	return &localLimiter{
+		entries:   make(map[string]*limiterEntry),
+		idle:      idle,
+		lastSweep: time.Now(),
+	}
+}
+
+func (l *localLimiter) Allow(_ context.Context, key string, policy LimitPolicy) (LimitResult, error) {
+	now := time.Now()
+
+	l.mu.Lock()
+	if now.Sub(l.lastSweep) >= l.idle {
+		l.sweep(now)
+	}
+	e, ok := l.entries[key]
+	if !ok {
+		e = &limiterEntry{limiter: rate.NewLimiter(rate.Limit(policy.Limit), policy.Burst)}
+		l.entries[key] = e
+	}
+	e.lastSeen = now
+	l.mu.Unlock()
+
+	res := LimitResult{Limit: policy.Burst}
+	r := e.limiter.ReserveN(now, 1)
+	if !r.OK() {
+		res.RetryAfter = time.Second
+		return res, nil
+	}
+	if delay := r.DelayFrom(now); delay > 0 {
+		r.CancelAt(now)
+		res.RetryAfter = delay
+		return res, nil
+	}
+	res.Allowed = true
+	res.Remaining = max(int(e.limiter.TokensAt(now)), 0)
+	return res, nil
+}
+
+// sweep 回收空闲超时的限流器，调用方需持有锁
+func (l *localLimiter) sweep(now time.Time) {
+	for key, e := range l.entries {
+		if now.Sub(e.lastSeen) >= l.idle {
+			delete(l.entries, key)
+		}
+	}
+	l.lastSweep = now
+}
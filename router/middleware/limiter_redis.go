@@ -0,0 +1,68 @@
+package middleware
+
+import (
+	"context"
+	"go-wire/logger"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const limiterKeyPrefix = "limiter:"
+
+// gcraScript GCRA 算法，以 redis 服务器时间为准保证多副本一致
+// 返回 {是否放行, 剩余令牌, 重试等待毫秒}
+var gcraScript = redis.NewScript(`
+redis.replicate_commands()
+local burst = tonumber(ARGV[1])
+local emission = 1000 / tonumber(ARGV[2])
+local t = redis.call('TIME')
+local now = tonumber(t[1]) * 1000 + tonumber(t[2]) / 1000
+local tat = tonumber(redis.call('GET', KEYS[1]))
+if not tat or tat < now then
+	tat = now
+end
+local newTat = tat + emission
+local diff = now - (newTat - emission * burst)
+if diff < 0 then
+	return {0, 0, math.ceil(-diff)}
+end
+redis.call('SET', KEYS[1], tostring(newTat), 'PX', math.max(math.ceil(newTat - now), 1))
+return {1, math.floor(diff / emission), 0}
+`)
+
+// redisLimiter 基于 redis 的分布式限流，redis 异常时按 fallback 降级
+type redisLimiter struct {
+	client   redis.UniversalClient
+	local    *localLimiter
+	fallback string
+	log      logger.Logger
+}
+
+func (l *redisLimiter) Allow(ctx context.Context, key string, policy LimitPolicy) (LimitResult, error) {
+	res := LimitResult{Limit: policy.Burst}
+	values, err := gcraScript.Run(ctx, l.client, []string{limiterKeyPrefix + key},
+		policy.Burst, strconv.FormatFloat(policy.Limit, 'f', -1, 64)).Int64Slice()
+	if err != nil {
+		l.log.Warn(ctx, "redis 限流异常，降级处理",
+			logger.StringAny("fallback", l.fallback),
+			logger.Error(err),
+		)
+		switch l.fallback {
+		case "open":
+			res.Allowed = true
+			return res, nil
+		case "closed":
+			res.RetryAfter = time.Second
+			return res, nil
+		default:
+			return l.local.Allow(ctx, key, policy)
+		}
+	}
+
+	res.Allowed = values[0] == 1
+	res.Remaining = int(values[1])
+	res.RetryAfter = time.Duration(values[2]) * time.Millisecond
+	return res, nil
+}
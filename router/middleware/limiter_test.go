@@ -0,0 +1,117 @@
+package middleware
+
+import (
+	"go-wire/config"
+	"go-wire/logger"
+	"go-wire/redis"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	goredis "github.com/redis/go-redis/v9"
+)
+
+func newLimiterEngine(t *testing.T, cfg *config.Config, rdb *redis.Redis) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	if err := engine.SetTrustedProxies(cfg.App.TrustedProxies); err != nil {
+		t.Fatal(err)
+	}
+	m, err := NewLimiterMiddleware(cfg, logger.NewNop(), rdb)
+	if err != nil {
+		t.Fatal(err)
+	}
+	engine.Use(m.Handler())
+	engine.GET("/api/test", func(ctx *gin.Context) { ctx.Status(http.StatusOK) })
+	engine.GET("/other", func(ctx *gin.Context) { ctx.Status(http.StatusOK) })
+	return engine
+}
+
+func serve(engine *gin.Engine, path, remoteAddr string, header ...string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	req.RemoteAddr = remoteAddr
+	for i := 0; i+1 < len(header); i += 2 {
+		req.Header.Set(header[i], header[i+1])
+	}
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, req)
+	return w
+}
+
+func TestLimiterPerClientIP(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.App.Limit, cfg.App.Burst = 0.001, 1
+	engine := newLimiterEngine(t, cfg, nil)
+
+	if w := serve(engine, "/other", "10.0.0.1:1234"); w.Code != http.StatusOK {
+		t.Fatalf("首个请求: status=%d", w.Code)
+	}
+	if w := serve(engine, "/other", "10.0.0.1:1234"); w.Code != http.StatusTooManyRequests {
+		t.Fatalf("超出容量: status=%d, want 429", w.Code)
+	}
+	if w := serve(engine, "/other", "10.0.0.2:1234"); w.Code != http.StatusOK {
+		t.Fatalf("其他客户端不受影响: status=%d", w.Code)
+	}
+}
+
+// 未配置可信代理时伪造 X-Forwarded-For 不能获得新的令牌桶
+func TestLimiterIgnoresUntrustedForwardedFor(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.App.Limit, cfg.App.Burst = 0.001, 1
+	engine := newLimiterEngine(t, cfg, nil)
+
+	serve(engine, "/other", "10.0.0.1:1234", "X-Forwarded-For", "1.1.1.1")
+	if w := serve(engine, "/other", "10.0.0.1:1234", "X-Forwarded-For", "2.2.2.2"); w.Code != http.StatusTooManyRequests {
+		t.Fatalf("伪造 X-Forwarded-For: status=%d, want 429", w.Code)
+	}
+
+	cfg.App.TrustedProxies = []string{"10.0.0.1"}
+	engine = newLimiterEngine(t, cfg, nil)
+	serve(engine, "/other", "10.0.0.1:1234", "X-Forwarded-For", "1.1.1.1")
+	if w := serve(engine, "/other", "10.0.0.1:1234", "X-Forwarded-For", "2.2.2.2"); w.Code != http.StatusOK {
+		t.Fatalf("可信代理转发的不同客户端: status=%d, want 200", w.Code)
+	}
+}
+
+// 路由策略独立计数，client 维度只使用认证通过的调用方
+func TestLimiterRoutePolicy(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Limiter.Routes = []config.LimiterRoute{{Method: http.MethodGet, Path: "/api/test", Key: "client", Limit: 0.001, Burst: 1}}
+	engine := newLimiterEngine(t, cfg, nil)
+
+	if w := serve(engine, "/api/test", "10.0.0.1:1234", "clientId", "a"); w.Code != http.StatusOK {
+		t.Fatalf("首个请求: status=%d", w.Code)
+	}
+	if w := serve(engine, "/api/test", "10.0.0.1:1234", "clientId", "b"); w.Code != http.StatusTooManyRequests {
+		t.Fatalf("未认证的 clientId 请求头不区分令牌桶: status=%d, want 429", w.Code)
+	}
+	if w := serve(engine, "/other", "10.0.0.1:1234"); w.Code != http.StatusOK {
+		t.Fatalf("未配置限流的路由: status=%d", w.Code)
+	}
+}
+
+// redis 不可用时按 fallback 降级
+func TestRedisLimiterFallback(t *testing.T) {
+	unreachable := goredis.NewClient(&goredis.Options{Addr: "127.0.0.1:1", DialTimeout: 50 * time.Millisecond, MaxRetries: -1})
+	defer unreachable.Close()
+	rdb := &redis.Redis{Clients: map[string]goredis.UniversalClient{"default": unreachable}}
+
+	for fallback, want := range map[string][]int{
+		"open":   {http.StatusOK, http.StatusOK},
+		"closed": {http.StatusTooManyRequests, http.StatusTooManyRequests},
+		"local":  {http.StatusOK, http.StatusTooManyRequests},
+	} {
+		cfg := &config.Config{}
+		cfg.App.Limit, cfg.App.Burst = 0.001, 1
+		cfg.Limiter.Driver, cfg.Limiter.Redis, cfg.Limiter.Fallback = "redis", "default", fallback
+		engine := newLimiterEngine(t, cfg, rdb)
+		for i, status := range want {
+			if w := serve(engine, "/other", "10.0.0.1:1234"); w.Code != status {
+				t.Fatalf("fallback=%s 第 %d 个请求: status=%d, want %d", fallback, i+1, w.Code, status)
+			}
+		}
+	}
+}
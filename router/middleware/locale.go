@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	"go-wire/config"
+	"go-wire/i18n"
+	"go-wire/reqctx"
+
+	"github.com/gin-gonic/gin"
+)
+
+type LocaleMiddleware struct {
+	param    string
+	fallback string
+}
+
+func NewLocaleMiddleware(cfg *config.Config) *LocaleMiddleware {
+	m := &LocaleMiddleware{param: cfg.I18n.Param, fallback: i18n.Default}
+	if locale, ok := i18n.Match(cfg.I18n.Default); ok {
+		m.fallback = locale
+	}
+	return m
+}
+
+// Handler 协商请求语言写入 context，响应信封与校验信息按该语言输出
+func (m *LocaleMiddleware) Handler() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		locale := i18n.Negotiate(ctx.Request, m.param, m.fallback)
+		ctx.Request = ctx.Request.WithContext(reqctx.WithLocale(ctx.Request.Context(), locale))
+		ctx.Header("Content-Language", locale)
+		ctx.Next()
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"go-wire/constant"
 	"go-wire/logger"
+	"go-wire/response"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -40,6 +41,7 @@ func (m *LoggerMiddleware) Handler() gin.HandlerFunc {
 			Error:     ctx.Errors.ByType(gin.ErrorTypePrivate).String(),
 			Cost:      cost.Seconds(),
 			Source:    ctx.Request.Host,
+			Codec:     ctx.GetString(response.CodecKey),
 		}
 
 		msg := fmt.Sprintf("%d %v %s %s", status, cost.Milliseconds(), layout.Method, layout.Path)
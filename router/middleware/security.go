@@ -0,0 +1,155 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"go-wire/config"
+	"go-wire/errs"
+	"go-wire/logger"
+	"go-wire/response"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultMaxBodySize = 4 << 20
+	// CSPNonceKey 当前请求 CSP nonce 在 gin.Context 中的键，页面内联脚本需带上该值
+	CSPNonceKey = "cspNonce"
+)
+
+type SecurityMiddleware struct {
+	log         logger.Logger
+	maxBodySize int64
+	routes      map[string]int64
+	headers     [][2]string // 预先拼接好的固定响应头
+	csp         string
+	cspHeader   string
+}
+
+func NewSecurityMiddleware(cfg *config.Config, log logger.Logger) *SecurityMiddleware {
+	c := cfg.Security
+	m := &SecurityMiddleware{
+		log:         log,
+		maxBodySize: c.MaxBodySize,
+		routes:      make(map[string]int64, len(c.Routes)),
+		csp:         c.Headers.CSP,
+		cspHeader:   "Content-Security-Policy",
+	}
+	if m.maxBodySize == 0 {
+		m.maxBodySize = defaultMaxBodySize
+	}
+	for _, r := range c.Routes {
+		m.routes[routeKey(r.Method, r.Path)] = r.MaxBodySize
+	}
+
+	h := c.Headers
+	if h.HSTS > 0 {
+		hsts := "max-age=" + strconv.Itoa(int(h.HSTS.Seconds()))
+		if h.HSTSSubdomains {
+			hsts += "; includeSubDomains"
+		}
+		if h.HSTSPreload {
+			hsts += "; preload"
+		}
+		m.headers = append(m.headers, [2]string{"Strict-Transport-Security", hsts})
+	}
+	if h.NoSniff {
+		m.headers = append(m.headers, [2]string{"X-Content-Type-Options", "nosniff"})
+	}
+	if h.FrameOptions != "" {
+		m.headers = append(m.headers, [2]string{"X-Frame-Options", h.FrameOptions})
+	}
+	if h.ReferrerPolicy != "" {
+		m.headers = append(m.headers, [2]string{"Referrer-Policy", h.ReferrerPolicy})
+	}
+	if h.CSPReportOnly {
+		m.cspHeader = "Content-Security-Policy-Report-Only"
+	}
+	return m
+}
+
+// Handler 输出安全响应头，限制请求体大小并拒绝格式错误的 Content-Type
+func (m *SecurityMiddleware) Handler() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		m.writeHeaders(ctx)
+
+		req := ctx.Request
+		if !hasBody(req) {
+			// 无请求体时不校验 Content-Type，GET 等请求携带的 Content-Type 不影响处理
+			ctx.Next()
+			return
+		}
+		if limit := m.limit(ctx); limit > 0 {
+			// 声明的长度已超出上限时不再读取请求体
+			if req.ContentLength > limit {
+				m.log.Warn(ctx, "请求体过大",
+					logger.StringAny("url", req.URL.Path),
+					logger.StringAny("contentLength", req.ContentLength),
+					logger.StringAny("limit", limit),
+				)
+				response.Error(ctx, errs.PayloadTooLarge)
+				return
+			}
+			// 分块传输等未声明长度的请求在读取超限时由绑定返回 413
+			req.Body = http.MaxBytesReader(ctx.Writer, req.Body, limit)
+		}
+		if contentType := req.Header.Get("Content-Type"); contentType != "" {
+			if _, _, err := mime.ParseMediaType(contentType); err != nil {
+				m.log.Warn(ctx, "请求体格式错误",
+					logger.StringAny("url", req.URL.Path),
+					logger.StringAny("contentType", contentType),
+				)
+				response.Error(ctx, errs.Unsupported.Wrap(err))
+				return
+			}
+		}
+		ctx.Next()
+	}
+}
+
+func (m *SecurityMiddleware) writeHeaders(ctx *gin.Context) {
+	h := ctx.Writer.Header()
+	for _, kv := range m.headers {
+		h.Set(kv[0], kv[1])
+	}
+	if m.csp == "" {
+		return
+	}
+	csp := m.csp
+	if strings.Contains(csp, "{nonce}") {
+		nonce := newNonce()
+		ctx.Set(CSPNonceKey, nonce)
+		csp = strings.ReplaceAll(csp, "{nonce}", nonce)
+	}
+	h.Set(m.cspHeader, csp)
+}
+
+// limit 路由级上限优先于全局上限，<=0 表示不限制
+func (m *SecurityMiddleware) limit(ctx *gin.Context) int64 {
+	path := ctx.FullPath()
+	if limit, ok := m.routes[routeKey(ctx.Request.Method, path)]; ok {
+		return limit
+	}
+	if limit, ok := m.routes[routeKey("", path)]; ok {
+		return limit
+	}
+	return m.maxBodySize
+}
+
+// hasBody 请求声明了长度或使用分块传输
+func hasBody(req *http.Request) bool {
+	if req.Body == nil || req.Body == http.NoBody {
+		return false
+	}
+	return req.ContentLength != 0 || len(req.TransferEncoding) > 0
+}
+
+func newNonce() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return base64.StdEncoding.EncodeToString(b)
+}
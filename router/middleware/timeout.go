@@ -0,0 +1,216 @@
+package middleware
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"go-wire/config"
+	"go-wire/errs"
+	"go-wire/logger"
+	"go-wire/response"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TimeoutMiddleware struct {
+	log     logger.Logger
+	timeout time.Duration
+	routes  map[string]time.Duration
+}
+
+func NewTimeoutMiddleware(cfg *config.Config, log logger.Logger) *TimeoutMiddleware {
+	c := cfg.Timeout
+	m := &TimeoutMiddleware{
+		log:     log,
+		timeout: c.Default,
+		routes:  make(map[string]time.Duration, len(c.Routes)),
+	}
+	for _, r := range c.Routes {
+		m.routes[routeKey(r.Method, r.Path)] = r.Timeout
+	}
+	return m
+}
+
+// Handler 为请求 context 设置截止时间，后续处理在独立协程中执行，响应先写入缓冲
+// 超时后立即返回 504，处理函数的后续输出被丢弃；等待处理协程退出后才归还 gin.Context
+func (m *TimeoutMiddleware) Handler() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		timeout := m.match(ctx)
+		if timeout <= 0 {
+			ctx.Next()
+			return
+		}
+
+		reqCtx, cancel := context.WithTimeout(ctx.Request.Context(), timeout)
+		defer cancel()
+		req := ctx.Request.WithContext(reqCtx)
+		ctx.Request = req
+
+		w := ctx.Writer
+		tw := &timeoutWriter{ResponseWriter: w, header: w.Header().Clone(), status: http.StatusOK}
+		ctx.Writer = tw
+
+		done := make(chan struct{})
+		panicked := make(chan any, 1)
+		go func() {
+			defer close(done)
+			defer func() {
+				if p := recover(); p != nil {
+					panicked <- p
+				}
+			}()
+			ctx.Next()
+		}()
+
+		select {
+		case <-done:
+		case <-reqCtx.Done():
+			m.expire(tw, req, done, timeout)
+		}
+
+		ctx.Writer = w
+		select {
+		case p := <-panicked:
+			// 交由 recovery 中间件处理
+			panic(p)
+		default:
+		}
+		if !tw.timedOut {
+			tw.commit()
+		} else {
+			ctx.Abort()
+		}
+	}
+}
+
+// expire 处理函数未在截止时间前完成，输出 504 后等待处理协程退出
+// 504 带 Content-Length 并立即 Flush，客户端无需等待处理函数返回；请求被取消(客户端断开)时仅丢弃响应
+func (m *TimeoutMiddleware) expire(tw *timeoutWriter, req *http.Request, done <-chan struct{}, timeout time.Duration) {
+	select {
+	case <-done:
+		// 截止时间与处理完成同时到达，以处理结果为准
+		return
+	default:
+	}
+	tw.mu.Lock()
+	tw.timedOut = true
+	tw.mu.Unlock()
+	if errors.Is(req.Context().Err(), context.DeadlineExceeded) {
+		// 处理协程仍在使用 gin.Context，日志只读取已保存的请求 context
+		m.log.Warn(req.Context(), "请求处理超时",
+			logger.StringAny("url", req.URL.Path),
+			logger.StringAny("timeout", timeout.String()),
+		)
+		response.WriteError(tw.ResponseWriter, req, errs.Timeout)
+		tw.ResponseWriter.Flush()
+	}
+	<-done
+}
+
+// match 路由级超时优先于默认超时
+func (m *TimeoutMiddleware) match(ctx *gin.Context) time.Duration {
+	path := ctx.FullPath()
+	if timeout, ok := m.routes[routeKey(ctx.Request.Method, path)]; ok {
+		return timeout
+	}
+	if timeout, ok := m.routes[routeKey("", path)]; ok {
+		return timeout
+	}
+	return m.timeout
+}
+
+// timeoutWriter 缓冲处理函数的响应头与响应体，未超时时由 commit 写出，超时后的写入返回 http.ErrHandlerTimeout
+type timeoutWriter struct {
+	gin.ResponseWriter
+	mu          sync.Mutex
+	header      http.Header
+	status      int
+	wroteHeader bool
+	buf         []byte
+	timedOut    bool
+}
+
+func (w *timeoutWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *timeoutWriter) WriteHeader(code int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if code > 0 && !w.wroteHeader {
+		w.status = code
+	}
+}
+
+func (w *timeoutWriter) WriteHeaderNow() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.wroteHeader = true
+}
+
+func (w *timeoutWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.timedOut {
+		return 0, http.ErrHandlerTimeout
+	}
+	w.wroteHeader = true
+	w.buf = append(w.buf, p...)
+	return len(p), nil
+}
+
+func (w *timeoutWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+func (w *timeoutWriter) Status() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.status
+}
+
+func (w *timeoutWriter) Size() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if !w.wroteHeader {
+		return -1
+	}
+	return len(w.buf)
+}
+
+func (w *timeoutWriter) Written() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.wroteHeader
+}
+
+// Flush 响应在处理完成后统一写出，缓冲期间无法流式输出
+func (w *timeoutWriter) Flush() {}
+
+func (w *timeoutWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return nil, nil, errors.New("超时控制的请求不支持 Hijack")
+}
+
+// commit 将缓冲的响应写到下层 writer
+func (w *timeoutWriter) commit() {
+	dst := w.ResponseWriter.Header()
+	for k := range dst {
+		if _, ok := w.header[k]; !ok {
+			dst.Del(k)
+		}
+	}
+	for k, v := range w.header {
+		dst[k] = v
+	}
+	w.ResponseWriter.WriteHeader(w.status)
+	if w.wroteHeader {
+		w.ResponseWriter.WriteHeaderNow()
+	}
+	if len(w.buf) > 0 {
+		_, _ = w.ResponseWriter.Write(w.buf)
+	}
+}
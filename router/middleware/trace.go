@@ -1,6 +1,8 @@
 package middleware
 
 import (
+	"go-wire/reqctx"
+
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 )
@@ -11,10 +13,11 @@ func NewTraceMiddleware() *TraceMiddleware {
 	return &TraceMiddleware{}
 }
 
+// Handler 生成链路 ID 写入请求 context，业务层通过 reqctx.TraceID 读取
 func (m *TraceMiddleware) Handler() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		traceID := uuid.New().String()
-		ctx.Set("TraceID", traceID)
+		ctx.Request = ctx.Request.WithContext(reqctx.WithTraceID(ctx.Request.Context(), traceID))
 		ctx.Next()
 	}
 }
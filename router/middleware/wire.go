@@ -5,9 +5,15 @@ import "github.com/google/wire"
 var ProviderSet = wire.NewSet(
 	NewAuthMiddleware,
 	NewCorsMiddleware,
-	NewLimiter,
 	NewLimiterMiddleware,
+	NewConcurrencyMiddleware,
 	NewTraceMiddleware,
 	NewErrorMiddleware,
 	NewLoggerMiddleware,
+	NewLocaleMiddleware,
+	NewCompressMiddleware,
+	NewSecurityMiddleware,
+	NewTimeoutMiddleware,
+	NewIdempotencyMiddleware,
+	NewCacheMiddleware,
 )
@@ -1,7 +1,12 @@
 package router
 
 import (
+	"expvar"
+	"go-wire/auth"
+	"go-wire/config"
 	"go-wire/controller"
+	"go-wire/openapi"
+	"go-wire/response"
 	"go-wire/router/middleware"
 
 	"github.com/gin-gonic/gin"
@@ -11,24 +16,60 @@ import (
 var ProviderSet = wire.NewSet(NewRouter)
 
 func NewRouter(
-	auth *middleware.AuthMiddleware,
+	cfg *config.Config,
+	authn *middleware.AuthMiddleware,
 	cors *middleware.CorsMiddleware,
 	trace *middleware.TraceMiddleware,
+	locale *middleware.LocaleMiddleware,
+	security *middleware.SecurityMiddleware,
+	compress *middleware.CompressMiddleware,
 	limiter *middleware.LimiterMiddleware,
+	idempotency *middleware.IdempotencyMiddleware,
+	cache *middleware.CacheMiddleware,
+	concurrency *middleware.ConcurrencyMiddleware,
+	timeout *middleware.TimeoutMiddleware,
 	logger *middleware.LoggerMiddleware,
 	error *middleware.ErrorMiddleware,
 	apiController *controller.ApiController,
-) *gin.Engine {
-	r := gin.New()
-	// 注册所有中间件
-	r.Use(auth.Handler())
-	r.Use(cors.Handler())
-	r.Use(trace.Handler())
-	r.Use(limiter.Handler())
-	r.Use(logger.Handler())
-	r.Use(error.Handler())
-	r.Use(trace.Handler())
-	apiGroup := r.Group("/")
-	apiController.RegisterRoutes(apiGroup)
-	return r
+	authz *auth.Authorizer,
+) (*gin.Engine, error) {
+	// 中间件执行顺序: recovery -> trace -> locale -> logger -> security -> cors -> concurrency -> timeout -> compress -> [组中间件]
+	// compress 在 timeout 之后，超时的 504 不经过压缩，直接以完整响应下发
+	b, err := NewBuilder(cfg.App.TrustedProxies, error.Handler(), trace.Handler())
+	if err != nil {
+		return nil, err
+	}
+	b.Use(locale.Handler(), logger.Handler(), security.Handler(), cors.Handler(), concurrency.Handler(), timeout.Handler(), compress.Handler())
+
+	// 公开路由，不经过认证与限流
+	public := b.Group("/")
+	public.GET("health", func(ctx *gin.Context) {
+		response.Success(ctx, nil)
+	})
+
+	// 业务路由: auth(公开路径跳过) -> limiter -> [路由授权] -> idempotency -> cache
+	// 幂等重放与缓存命中在授权之后，未授权或权限被收回的主体无法取回已保存的响应
+	apiGroup := b.Group("/",
+		Skip(cfg.Router.PublicPaths, authn.Handler()),
+		limiter.Handler(),
+	)
+	apiController.RegisterRoutes(authz.Group(apiGroup).Use(idempotency.Handler(), cache.Handler()))
+
+	// 路由授权策略审计与运行指标(expvar)
+	engine := b.Engine()
+	admin := authz.Group(apiGroup, auth.RequireRoles("admin"))
+	admin.GET("authz/routes", func(ctx *gin.Context) {
+		response.Success(ctx, authz.Audit(engine.Routes()))
+	})
+	admin.GET("debug/vars", gin.WrapH(expvar.Handler()))
+
+	// 接口文档: /openapi.json 与 /docs
+	if cfg.OpenAPI.Enabled {
+		info := openapi.Info{Title: cfg.OpenAPI.Title, Version: cfg.OpenAPI.Version, Description: cfg.OpenAPI.Description}
+		if info.Title == "" {
+			info.Title = cfg.App.Name
+		}
+		openapi.Register(public, info, engine.Routes, authz)
+	}
+	return engine, nil
 }
@@ -1,10 +1,10 @@
 package service
 
 import (
+	"context"
 	"go-wire/logger"
+	"go-wire/paging"
 	"go-wire/repo"
-
-	"github.com/gin-gonic/gin"
 )
 
 type ApiService struct {
@@ -16,7 +16,7 @@ func NewApiService(repo *repo.ApiRepo, log logger.Logger) *ApiService {
 	return &ApiService{repo: repo, log: log}
 }
 
-func (s *ApiService) Test(ctx *gin.Context, id string) (string, error) {
+func (s *ApiService) Test(ctx context.Context, id string) (string, error) {
 	user, err := s.repo.Test(ctx, id)
 	if err != nil {
 		s.log.Error(ctx, "Test Service", logger.Error(err))
@@ -24,3 +24,19 @@ func (s *ApiService) Test(ctx *gin.Context, id string) (string, error) {
 	}
 	return user, nil
 }
+
+// testSorts 列表允许的排序字段
+var testSorts = paging.Sortable{"createdAt": "createdAt"}
+
+func (s *ApiService) List(ctx context.Context, q paging.Query) (*paging.Page[string], error) {
+	orders, err := q.Orders(ctx, testSorts, paging.Order{Field: "createdAt", Desc: true})
+	if err != nil {
+		return nil, err
+	}
+	page, err := s.repo.List(ctx, q, orders[0].Desc)
+	if err != nil {
+		s.log.Error(ctx, "List Service", logger.Error(err))
+		return nil, err
+	}
+	return page, nil
+}
@@ -0,0 +1,206 @@
+package validation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-wire/config"
+	"go-wire/i18n"
+	"go-wire/redis"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+)
+
+var mobilePattern = regexp.MustCompile(`^1[3-9]\d{9}$`)
+
+func builtinRules() []Rule {
+	return []Rule{
+		{
+			Tag: "mobile",
+			Fn: func(_ context.Context, fl validator.FieldLevel) bool {
+				return mobilePattern.MatchString(fl.Field().String())
+			},
+			Messages: map[string]string{
+				i18n.ZH: "{0}必须是有效的手机号码",
+				i18n.EN: "{0} must be a valid mobile number",
+			},
+		},
+		{
+			Tag: "idcard",
+			Fn: func(_ context.Context, fl validator.FieldLevel) bool {
+				return validIDCard(fl.Field().String())
+			},
+			Messages: map[string]string{
+				i18n.ZH: "{0}必须是有效的身份证号码",
+				i18n.EN: "{0} must be a valid ID card number",
+			},
+		},
+		{
+			Tag: "enum",
+			Fn: func(_ context.Context, fl validator.FieldLevel) bool {
+				return inEnum(fl.Param(), fmt.Sprint(fl.Field().Interface()))
+			},
+			Messages: map[string]string{
+				i18n.ZH: "{0}必须是[{1}]中的一个",
+				i18n.EN: "{0} must be one of [{1}]",
+			},
+			Param: func(name string) string {
+				return strings.Join(enumValues(name), " ")
+			},
+		},
+		{
+			Tag: "datebefore",
+			Fn: func(_ context.Context, fl validator.FieldLevel) bool {
+				return compareDate(fl, func(a, b time.Time) bool { return !a.After(b) })
+			},
+			Messages: map[string]string{
+				i18n.ZH: "{0}不能晚于{1}",
+				i18n.EN: "{0} must not be later than {1}",
+			},
+		},
+		{
+			Tag: "dateafter",
+			Fn: func(_ context.Context, fl validator.FieldLevel) bool {
+				return compareDate(fl, func(a, b time.Time) bool { return !a.Before(b) })
+			},
+			Messages: map[string]string{
+				i18n.ZH: "{0}不能早于{1}",
+				i18n.EN: "{0} must not be earlier than {1}",
+			},
+		},
+	}
+}
+
+var (
+	idCardWeights = []int{7, 9, 10, 5, 8, 4, 2, 1, 6, 3, 7, 9, 10, 5, 8, 4, 2}
+	idCardChecks  = "10X98765432"
+)
+
+// validIDCard 校验 18 位居民身份证号的出生日期与校验位
+func validIDCard(s string) bool {
+	s = strings.ToUpper(s)
+	if len(s) != 18 {
+		return false
+	}
+	sum := 0
+	for i := 0; i < 17; i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+		sum += int(s[i]-'0') * idCardWeights[i]
+	}
+	if _, err := time.Parse("20060102", s[6:14]); err != nil {
+		return false
+	}
+	return s[17] == idCardChecks[sum%11]
+}
+
+var enums = struct {
+	sync.RWMutex
+	values map[string][]string
+}{values: make(map[string][]string)}
+
+// RegisterEnum 注册枚举取值集合，字段通过 binding:"enum=name" 引用，应在包初始化时调用
+func RegisterEnum(name string, values ...string) {
+	enums.Lock()
+	defer enums.Unlock()
+	enums.values[name] = values
+}
+
+func enumValues(name string) []string {
+	enums.RLock()
+	defer enums.RUnlock()
+	return enums.values[name]
+}
+
+func inEnum(name, value string) bool {
+	for _, v := range enumValues(name) {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+var dateLayouts = []string{time.RFC3339, time.DateTime, time.DateOnly}
+
+// compareDate 比较当前字段与参数指定字段的日期，任一为空时跳过，交由 required 校验
+func compareDate(fl validator.FieldLevel, ok func(a, b time.Time) bool) bool {
+	other, _, _, found := fl.GetStructFieldOK2()
+	if !found {
+		return false
+	}
+	a, aSet, aValid := toTime(fl.Field())
+	b, bSet, bValid := toTime(other)
+	if !aValid || !bValid {
+		return false
+	}
+	if !aSet || !bSet {
+		return true
+	}
+	return ok(a, b)
+}
+
+// toTime 解析 time.Time 或日期字符串，返回时间、是否有值、格式是否有效
+func toTime(v reflect.Value) (time.Time, bool, bool) {
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return time.Time{}, false, true
+		}
+		v = v.Elem()
+	}
+	if t, ok := v.Interface().(time.Time); ok {
+		return t, !t.IsZero(), true
+	}
+	if v.Kind() != reflect.String {
+		return time.Time{}, false, false
+	}
+	if v.String() == "" {
+		return time.Time{}, false, true
+	}
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, v.String()); err == nil {
+			return t, true, true
+		}
+	}
+	return time.Time{}, true, false
+}
+
+// uniqueRule 值不存在于 Redis 集合 unique:<param> 中时通过，集合由业务写入时维护
+// 标签为 redis_unique，避免覆盖 validator 内置的 unique(切片元素唯一)
+// Redis 不可用时通过 Fail 上报，请求以服务不可用失败，不放行也不误报为参数错误
+func uniqueRule(cfg *config.Config, rdb *redis.Redis) Rule {
+	name := cfg.Validation.Redis
+	if name == "" {
+		name = "default"
+	}
+	return Rule{
+		Tag: "redis_unique",
+		Fn: func(ctx context.Context, fl validator.FieldLevel) bool {
+			if rdb == nil {
+				Fail(ctx, errors.New("唯一性校验失败: 未配置 Redis"))
+				return true
+			}
+			client, err := rdb.Client(name)
+			if err != nil {
+				Fail(ctx, fmt.Errorf("唯一性校验失败: %w", err))
+				return true
+			}
+			exists, err := client.SIsMember(ctx, "unique:"+fl.Param(), fmt.Sprint(fl.Field().Interface())).Result()
+			if err != nil {
+				Fail(ctx, fmt.Errorf("唯一性校验失败 [%s]: %w", fl.Param(), err))
+				return true
+			}
+			return !exists
+		},
+		Messages: map[string]string{
+			i18n.ZH: "{0}已存在",
+			i18n.EN: "{0} already exists",
+		},
+	}
+}
@@ -0,0 +1,170 @@
+package validation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-wire/config"
+	"go-wire/errs"
+	"go-wire/i18n"
+	"go-wire/logger"
+	"go-wire/redis"
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/locales/en"
+	"github.com/go-playground/locales/zh"
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+	enTranslations "github.com/go-playground/validator/v10/translations/en"
+	zhTranslations "github.com/go-playground/validator/v10/translations/zh"
+	"github.com/google/wire"
+)
+
+var ProviderSet = wire.NewSet(NewValidator)
+
+// Rule 自定义校验标签，Messages 为各语言的提示模板，{0} 为字段名，{1} 为参数
+// Fn 为空时仅注册提示，用于结构体级校验上报的标签
+type Rule struct {
+	Tag        string
+	Fn         validator.FuncCtx
+	CallIfNull bool                      // 字段为空值时也执行校验
+	Messages   map[string]string         // 语言 -> 提示模板
+	Param      func(param string) string // 提示中参数的展示形式，默认原样输出
+}
+
+// Validator 基于 gin binding.Validator 引擎的校验器，管理自定义标签与多语言提示
+type Validator struct {
+	engine *validator.Validate
+	uni    *ut.UniversalTranslator
+	log    logger.Logger
+}
+
+func NewValidator(cfg *config.Config, log logger.Logger, rdb *redis.Redis) (*Validator, error) {
+	engine, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return nil, errors.New("校验器初始化失败: 不支持的校验引擎")
+	}
+	engine.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+
+	zhT, enT := zh.New(), en.New()
+	v := &Validator{engine: engine, uni: ut.New(zhT, zhT, enT), log: log}
+
+	defaults := map[string]func(*validator.Validate, ut.Translator) error{
+		i18n.ZH: zhTranslations.RegisterDefaultTranslations,
+		i18n.EN: enTranslations.RegisterDefaultTranslations,
+	}
+	for _, locale := range i18n.Supported {
+		trans, found := v.uni.GetTranslator(locale)
+		if !found {
+			return nil, fmt.Errorf("翻译器获取失败: %s", locale)
+		}
+		if err := defaults[locale](engine, trans); err != nil {
+			return nil, fmt.Errorf("翻译器注册失败: %s: %w", locale, err)
+		}
+	}
+
+	if err := v.Register(builtinRules()...); err != nil {
+		return nil, err
+	}
+	if err := v.Register(uniqueRule(cfg, rdb)); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// Register 注册自定义校验标签及其各语言提示，应在服务启动前调用
+// 依赖注入的服务可在构造函数中通过闭包注册需要访问外部资源的规则
+func (v *Validator) Register(rules ...Rule) error {
+	for _, r := range rules {
+		if r.Fn != nil {
+			if err := v.engine.RegisterValidationCtx(r.Tag, r.Fn, r.CallIfNull); err != nil {
+				return fmt.Errorf("校验标签 %s 注册失败: %w", r.Tag, err)
+			}
+		}
+		for _, locale := range i18n.Supported {
+			if err := v.registerMessage(locale, r); err != nil {
+				return fmt.Errorf("校验标签 %s 提示注册失败: %w", r.Tag, err)
+			}
+		}
+	}
+	return nil
+}
+
+func (v *Validator) registerMessage(locale string, r Rule) error {
+	msg, ok := r.Messages[locale]
+	if !ok {
+		msg, ok = r.Messages[i18n.Default]
+	}
+	if !ok {
+		return nil
+	}
+	trans, _ := v.uni.GetTranslator(locale)
+	return v.engine.RegisterTranslation(r.Tag, trans,
+		func(t ut.Translator) error {
+			return t.Add(r.Tag, msg, true)
+		},
+		func(t ut.Translator, fe validator.FieldError) string {
+			param := fe.Param()
+			if r.Param != nil {
+				param = r.Param(param)
+			}
+			res, err := t.T(r.Tag, fe.Field(), param)
+			if err != nil {
+				return fe.Error()
+			}
+			return res
+		},
+	)
+}
+
+// RegisterStruct 注册结构体级校验，用于跨字段规则
+// 通过 StructLevel.ReportError 上报的标签需另行以 Rule 注册提示
+func (v *Validator) RegisterStruct(fn validator.StructLevelFuncCtx, types ...any) {
+	v.engine.RegisterStructValidationCtx(fn, types...)
+}
+
+// Translator 按语言返回校验提示翻译器，不支持的语言回退到默认语言
+func (v *Validator) Translator(locale string) ut.Translator {
+	trans, _ := v.uni.GetTranslator(locale)
+	return trans
+}
+
+// Struct 携带请求 context 校验参数，自定义规则可读取 context 访问外部资源
+// 规则通过 Fail 上报外部资源错误时返回 errs.Unavailable，而不是字段校验失败
+func (v *Validator) Struct(ctx context.Context, obj any) error {
+	value := reflect.ValueOf(obj)
+	for value.Kind() == reflect.Pointer && !value.IsNil() {
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct {
+		return binding.Validator.ValidateStruct(obj)
+	}
+	failure := new(ruleFailure)
+	err := v.engine.StructCtx(context.WithValue(ctx, ruleFailureKey{}, failure), obj)
+	if failure.err != nil {
+		return errs.Unavailable.Wrap(failure.err)
+	}
+	return err
+}
+
+type ruleFailureKey struct{}
+
+// ruleFailure 记录单次校验中规则上报的首个外部资源错误
+type ruleFailure struct {
+	err error
+}
+
+// Fail 供自定义规则上报外部资源错误(如 Redis 不可用)，规则随后应返回 true，避免误报字段错误
+func Fail(ctx context.Context, err error) {
+	if f, ok := ctx.Value(ruleFailureKey{}).(*ruleFailure); ok && f.err == nil {
+		f.err = err
+	}
+}
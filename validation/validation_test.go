@@ -0,0 +1,53 @@
+package validation
+
+import (
+	"context"
+	"errors"
+	"go-wire/config"
+	"go-wire/errs"
+	"go-wire/logger"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+)
+
+func TestRules(t *testing.T) {
+	v, err := NewValidator(&config.Config{}, logger.NewNop(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type request struct {
+		Mobile string   `json:"mobile" binding:"omitempty,mobile"`
+		Tags   []string `json:"tags" binding:"unique"`
+	}
+	if err = v.Struct(context.Background(), &request{Mobile: "13800138000", Tags: []string{"a", "b"}}); err != nil {
+		t.Fatalf("合法参数: %v", err)
+	}
+	// 内置 unique 仍用于切片元素唯一校验
+	err = v.Struct(context.Background(), &request{Tags: []string{"a", "a"}})
+	var verrs validator.ValidationErrors
+	if !errors.As(err, &verrs) || verrs[0].Tag() != "unique" {
+		t.Fatalf("切片元素重复: %v", err)
+	}
+	err = v.Struct(context.Background(), &request{Mobile: "12345"})
+	if !errors.As(err, &verrs) || verrs[0].Tag() != "mobile" {
+		t.Fatalf("手机号格式错误: %v", err)
+	}
+}
+
+// Redis 不可用时返回服务不可用，而不是放行或报告字段错误
+func TestRedisUniqueUnavailable(t *testing.T) {
+	v, err := NewValidator(&config.Config{}, logger.NewNop(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type request struct {
+		Name string `json:"name" binding:"redis_unique=user_name"`
+	}
+	err = v.Struct(context.Background(), &request{Name: "tom"})
+	if !errors.Is(err, errs.Unavailable) {
+		t.Fatalf("err=%v, want %v", err, errs.Unavailable)
+	}
+}